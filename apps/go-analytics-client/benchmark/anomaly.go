@@ -0,0 +1,190 @@
+package benchmark
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// timelineSamplesPerSecond bounds the latencies kept per second for segment percentiles
+	timelineSamplesPerSecond = 200
+	// minRegimeSeconds is the shortest latency regime the detector will report
+	minRegimeSeconds = 5
+	// minRegimeShift is the smallest relative change in mean latency treated as a new regime
+	minRegimeShift = 0.10
+)
+
+// timelineBucket holds one second of successful request latencies
+type timelineBucket struct {
+	count   int64
+	sumMs   float64
+	samples []float64
+}
+
+// LatencyTimeline accumulates a per-second latency timeline for regime detection
+type LatencyTimeline struct {
+	mu      sync.Mutex
+	startMs int64
+	buckets []timelineBucket
+	rng     *rand.Rand
+}
+
+// NewLatencyTimeline creates a timeline whose first bucket starts at startTime,
+// drawing its reservoir samples from rng
+func NewLatencyTimeline(startTime time.Time, rng *rand.Rand) *LatencyTimeline {
+	return &LatencyTimeline{
+		startMs: startTime.UnixMilli(),
+		rng:     rng,
+	}
+}
+
+// Add records a successful request in the second it started
+func (t *LatencyTimeline) Add(result *QueryExecutionMetrics) {
+	slot := (result.AbsoluteStartTimeMs - t.startMs) / 1000
+	if slot < 0 {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for int64(len(t.buckets)) <= slot {
+		t.buckets = append(t.buckets, timelineBucket{})
+	}
+	bucket := &t.buckets[slot]
+	bucket.count++
+	bucket.sumMs += result.DurationMs
+
+	// Reservoir sampling keeps a uniform sample once the bucket is full
+	if len(bucket.samples) < timelineSamplesPerSecond {
+		bucket.samples = append(bucket.samples, result.DurationMs)
+	} else if j := t.rng.Int63n(bucket.count); j < timelineSamplesPerSecond {
+		bucket.samples[j] = result.DurationMs
+	}
+}
+
+// LatencyRegime is a stretch of the run with a stable latency level
+type LatencyRegime struct {
+	StartMs  int64
+	EndMs    int64
+	Requests int64
+	MeanMs   float64
+	P50Ms    float64
+	P99Ms    float64
+}
+
+// DetectRegimes splits the timeline at detected latency change points
+func (t *LatencyTimeline) DetectRegimes() []LatencyRegime {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	// Seconds without traffic carry no latency signal, so detect over active seconds only
+	var active []int
+	var means []float64
+	for i, bucket := range t.buckets {
+		if bucket.count > 0 {
+			active = append(active, i)
+			means = append(means, bucket.sumMs/float64(bucket.count))
+		}
+	}
+	if len(active) == 0 {
+		return nil
+	}
+
+	bounds := append([]int{0}, detectChangePoints(means, minRegimeSeconds)...)
+	bounds = append(bounds, len(means))
+
+	regimes := make([]LatencyRegime, 0, len(bounds)-1)
+	for i := 0; i+1 < len(bounds); i++ {
+		first, last := active[bounds[i]], active[bounds[i+1]-1]
+
+		var requests int64
+		var sumMs float64
+		var samples []float64
+		for slot := first; slot <= last; slot++ {
+			bucket := t.buckets[slot]
+			requests += bucket.count
+			sumMs += bucket.sumMs
+			samples = append(samples, bucket.samples...)
+		}
+		sort.Float64s(samples)
+
+		regimes = append(regimes, LatencyRegime{
+			StartMs:  t.startMs + int64(first)*1000,
+			EndMs:    t.startMs + int64(last+1)*1000,
+			Requests: requests,
+			MeanMs:   sumMs / float64(requests),
+			P50Ms:    percentileOfSorted(samples, 50),
+			P99Ms:    percentileOfSorted(samples, 99),
+		})
+	}
+	return regimes
+}
+
+// detectChangePoints finds level shifts in series using binary segmentation on the
+// sum of squared errors. A split is accepted when it reduces the error by more than a
+// BIC-style penalty scaled by the series' noise (estimated from successive differences
+// so that the shifts themselves do not inflate it) and the means on either side differ
+// by at least minRegimeShift, so slow drift is not reported as a regime change.
+func detectChangePoints(series []float64, minSegment int) []int {
+	n := len(series)
+	if n < 2*minSegment {
+		return nil
+	}
+
+	prefix := make([]float64, n+1)
+	prefixSq := make([]float64, n+1)
+	for i, v := range series {
+		prefix[i+1] = prefix[i] + v
+		prefixSq[i+1] = prefixSq[i] + v*v
+	}
+	mean := func(lo, hi int) float64 {
+		return (prefix[hi] - prefix[lo]) / float64(hi-lo)
+	}
+	cost := func(lo, hi int) float64 {
+		sum := prefix[hi] - prefix[lo]
+		return (prefixSq[hi] - prefixSq[lo]) - sum*sum/float64(hi-lo)
+	}
+
+	diffs := make([]float64, n-1)
+	for i := 1; i < n; i++ {
+		diffs[i-1] = math.Abs(series[i] - series[i-1])
+	}
+	sort.Float64s(diffs)
+	sigma := percentileOfSorted(diffs, 50) / (0.6745 * math.Sqrt2)
+	if sigma == 0 {
+		sigma = 1e-9
+	}
+	penalty := 3 * sigma * sigma * math.Log(float64(n))
+
+	var points []int
+	var split func(lo, hi int)
+	split = func(lo, hi int) {
+		if hi-lo < 2*minSegment {
+			return
+		}
+		total := cost(lo, hi)
+		best, bestGain := -1, 0.0
+		for k := lo + minSegment; k <= hi-minSegment; k++ {
+			if gain := total - cost(lo, k) - cost(k, hi); gain > bestGain {
+				best, bestGain = k, gain
+			}
+		}
+		if best < 0 || bestGain <= penalty {
+			return
+		}
+		left, right := mean(lo, best), mean(best, hi)
+		if math.Abs(left-right) < minRegimeShift*math.Max(left, right) {
+			return
+		}
+		split(lo, best)
+		points = append(points, best)
+		split(best, hi)
+	}
+	split(0, n)
+
+	return points
+}
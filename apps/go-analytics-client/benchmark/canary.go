@@ -0,0 +1,115 @@
+package benchmark
+
+import (
+	"context"
+	"encoding/json"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+)
+
+// CanaryTrace is the verbose record of one fully traced request
+type CanaryTrace struct {
+	TimestampMs       int64         `json:"timestamp_ms"`
+	Canary            int64         `json:"canary"`
+	SDKType           string        `json:"sdk_type"`
+	QueryName         string        `json:"query_name"`
+	Query             string        `json:"query"`
+	Success           bool          `json:"success"`
+	ErrorMessage      string        `json:"error_message,omitempty"`
+	DurationMs        float64       `json:"duration_ms"`
+	TimeToFirstRowMs  float64       `json:"time_to_first_row_ms"`
+	RowCount          int           `json:"row_count"`
+	ResponseBytes     int64         `json:"response_bytes"`
+	RequestID         string        `json:"request_id,omitempty"`
+	ClientContextID   string        `json:"client_context_id,omitempty"`
+	Node              string        `json:"node,omitempty"`
+	ServerElapsedMs   float64       `json:"server_elapsed_ms"`
+	ServerExecutionMs float64       `json:"server_execution_ms"`
+	ResultCount       uint64        `json:"result_count"`
+	ResultSize        uint64        `json:"result_size"`
+	ProcessedObjects  uint64        `json:"processed_objects"`
+	Warnings          []string      `json:"warnings,omitempty"`
+	Phases            *PhaseTimings `json:"phases,omitempty"`
+}
+
+// CanaryRecorder executes one fully traced request per interval outside the
+// worker pool and writes it to its own file, so the bulk of requests stay lightweight
+type CanaryRecorder struct {
+	handler    AnalyticsSDKHandler
+	query      string
+	queryName  string
+	params     *ParamsTemplate
+	interval   time.Duration
+	outputFile string
+	rng        *rand.Rand
+
+	count int64
+	done  chan struct{}
+}
+
+// NewCanaryRecorder creates a recorder tracing query once per interval, rendering
+// its parameters with rng
+func NewCanaryRecorder(handler AnalyticsSDKHandler, query, queryName string, params *ParamsTemplate, interval time.Duration, outputFile string, rng *rand.Rand) *CanaryRecorder {
+	return &CanaryRecorder{
+		handler:    handler,
+		query:      query,
+		queryName:  queryName,
+		params:     params,
+		interval:   interval,
+		outputFile: outputFile,
+		rng:        rng,
+		done:       make(chan struct{}),
+	}
+}
+
+// Start traces one request on every interval until ctx is cancelled
+func (c *CanaryRecorder) Start(ctx context.Context) {
+	defer close(c.done)
+
+	if err := os.MkdirAll(filepath.Dir(c.outputFile), 0755); err != nil {
+		logErrorf("Failed to create canary output directory: %v", err)
+		return
+	}
+	file, err := os.Create(c.outputFile)
+	if err != nil {
+		logErrorf("Failed to create canary output file: %v", err)
+		return
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			canary := atomic.AddInt64(&c.count, 1)
+			var params QueryParameters
+			if c.params != nil {
+				params = c.params.Render(canary, c.rng)
+			}
+			trace := c.handler.TraceQuery(c.query, params, canary)
+			trace.Canary = canary
+			trace.QueryName = c.queryName
+			if err := encoder.Encode(trace); err != nil {
+				logErrorf("Failed to encode canary trace: %v", err)
+			}
+		}
+	}
+}
+
+// Wait blocks until the recorder has stopped
+func (c *CanaryRecorder) Wait() {
+	<-c.done
+}
+
+// Count returns the number of canary requests traced
+func (c *CanaryRecorder) Count() int64 {
+	return atomic.LoadInt64(&c.count)
+}
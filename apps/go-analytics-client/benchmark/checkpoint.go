@@ -0,0 +1,157 @@
+package benchmark
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+)
+
+// Checkpoint is one periodic snapshot of a long run. The interval fields cover
+// the requests completed since the previous checkpoint; the total fields cover the
+// run so far.
+type Checkpoint struct {
+	TimestampMs    int64              `json:"timestamp_ms"`
+	ElapsedMs      int64              `json:"elapsed_ms"`
+	Requests       int64              `json:"requests"`
+	Successes      int64              `json:"successes"`
+	SuccessRate    float64            `json:"success_rate"`
+	ThroughputRPS  float64            `json:"throughput_rps"`
+	Latency        LatencyPercentiles `json:"latency"`
+	TotalRequests  int64              `json:"total_requests"`
+	TotalSuccesses int64              `json:"total_successes"`
+	LatencyTotal   LatencyPercentiles `json:"latency_total"`
+	Runtime        RuntimeStats       `json:"runtime"`
+}
+
+// checkpointPath returns where the checkpoints for outputFile are written
+func checkpointPath(outputFile string) string {
+	return artifactBase(outputFile) + ".checkpoints.jsonl"
+}
+
+// CheckpointRecorder appends a Checkpoint to a JSON Lines file every interval, so
+// a soak run's latency drift and client memory can be watched while it runs. The
+// interval histogram is replaced at every checkpoint, and the run-wide one is the
+// runner's fixed-size histogram, so memory does not grow with the run length.
+type CheckpointRecorder struct {
+	path     string
+	interval time.Duration
+	digits   int
+	total    *LatencyRecorder
+
+	mu             sync.Mutex
+	window         *LatencyRecorder
+	requests       int64
+	successes      int64
+	totalRequests  int64
+	totalSuccesses int64
+
+	// Only the Start goroutine touches these
+	lastCheckAt time.Time
+	written     int64
+	stopped     chan struct{}
+}
+
+// NewCheckpointRecorder creates a recorder writing to path every interval, with
+// run-wide percentiles read from total
+func NewCheckpointRecorder(path string, interval time.Duration, significantDigits int, total *LatencyRecorder) *CheckpointRecorder {
+	return &CheckpointRecorder{
+		path:     path,
+		interval: interval,
+		digits:   significantDigits,
+		total:    total,
+		window:   NewLatencyRecorder(significantDigits),
+		stopped:  make(chan struct{}),
+	}
+}
+
+// Add records one completed request in the current interval
+func (c *CheckpointRecorder) Add(result *QueryExecutionMetrics) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.requests++
+	c.totalRequests++
+	if result.Success {
+		c.successes++
+		c.totalSuccesses++
+		c.window.Record(result.DurationMs)
+	}
+}
+
+// Start writes a checkpoint every interval from startTime until ctx is cancelled,
+// then writes a final one for the partial interval
+func (c *CheckpointRecorder) Start(ctx context.Context, startTime time.Time) {
+	defer close(c.stopped)
+
+	file, err := os.Create(c.path)
+	if err != nil {
+		logErrorf("Failed to create checkpoint file: %v", err)
+		return
+	}
+	defer file.Close()
+	encoder := json.NewEncoder(file)
+
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	c.lastCheckAt = startTime
+	for {
+		select {
+		case <-ctx.Done():
+			c.write(encoder, startTime)
+			return
+		case <-ticker.C:
+			c.write(encoder, startTime)
+		}
+	}
+}
+
+// write takes the current interval's counts and histogram, starts a new interval
+// and appends the checkpoint
+func (c *CheckpointRecorder) write(encoder *json.Encoder, startTime time.Time) {
+	now := time.Now()
+
+	c.mu.Lock()
+	window, requests, successes := c.window, c.requests, c.successes
+	totalRequests, totalSuccesses := c.totalRequests, c.totalSuccesses
+	c.window = NewLatencyRecorder(c.digits)
+	c.requests, c.successes = 0, 0
+	c.mu.Unlock()
+
+	checkpoint := Checkpoint{
+		TimestampMs:    now.UnixMilli(),
+		ElapsedMs:      now.Sub(startTime).Milliseconds(),
+		Requests:       requests,
+		Successes:      successes,
+		Latency:        NewLatencyPercentiles(window.Percentiles(summaryPercentiles...)),
+		TotalRequests:  totalRequests,
+		TotalSuccesses: totalSuccesses,
+		LatencyTotal:   NewLatencyPercentiles(c.total.Percentiles(summaryPercentiles...)),
+		Runtime:        runtimeStats(),
+	}
+	if requests > 0 {
+		checkpoint.SuccessRate = float64(successes) * 100.0 / float64(requests)
+	}
+	if elapsed := now.Sub(c.lastCheckAt).Seconds(); elapsed > 0 {
+		checkpoint.ThroughputRPS = float64(requests) / elapsed
+	}
+	c.lastCheckAt = now
+
+	if err := encoder.Encode(checkpoint); err != nil {
+		logErrorf("Failed to write checkpoint: %v", err)
+		return
+	}
+	c.written++
+}
+
+// Wait blocks until the final checkpoint has been written
+func (c *CheckpointRecorder) Wait() {
+	<-c.stopped
+}
+
+// Count returns the number of checkpoints written. Call it after Wait.
+func (c *CheckpointRecorder) Count() int64 {
+	return c.written
+}
@@ -0,0 +1,86 @@
+package benchmark
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"path/filepath"
+	"strings"
+)
+
+// SDKTypeBoth runs the operational and then the enterprise SDK with the same settings
+const SDKTypeBoth = "both"
+
+// comparisonSDKTypes are the SDKs a comparison run measures, in order
+var comparisonSDKTypes = []string{SDKTypeOperational, SDKTypeEnterprise}
+
+// sdkOutputPath suffixes the output file with the SDK type before the extension,
+// e.g. results.jsonl becomes results.operational.jsonl
+func sdkOutputPath(outputFile, sdkType string) string {
+	ext := filepath.Ext(outputFile)
+	return strings.TrimSuffix(outputFile, ext) + "." + sdkType + ext
+}
+
+// runComparison runs the whole test once per SDK, back to back with identical
+// settings, and then logs the two summaries side by side. A failed run does not
+// stop the other one; a shutdown signal does.
+func (r *SimpleAnalyticsRunner) runComparison(ctx context.Context) error {
+	reports := make(map[string]*SummaryReport, len(comparisonSDKTypes))
+	var errs []error
+	for _, sdkType := range comparisonSDKTypes {
+		if ctx.Err() != nil {
+			break
+		}
+		log.Printf("🔀 Comparison run: %s SDK", sdkType)
+
+		run := *r
+		run.sequenceCounter = 0
+		run.config.SDKType = sdkType
+		run.config.OutputFile = sdkOutputPath(r.config.OutputFile, sdkType)
+		if r.config.WarmupOutputFile != "" {
+			run.config.WarmupOutputFile = sdkOutputPath(r.config.WarmupOutputFile, sdkType)
+		}
+		if r.config.VerificationOutputFile != "" {
+			run.config.VerificationOutputFile = sdkOutputPath(r.config.VerificationOutputFile, sdkType)
+		}
+
+		report, err := run.runSDK(ctx)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", sdkType, err))
+		}
+		if report != nil {
+			reports[sdkType] = report
+		}
+	}
+
+	reportComparison(reports)
+	return errors.Join(errs...)
+}
+
+// reportComparison logs throughput and latency percentiles of each SDK side by side
+func reportComparison(reports map[string]*SummaryReport) {
+	operational, enterprise := reports[SDKTypeOperational], reports[SDKTypeEnterprise]
+	if operational == nil || enterprise == nil {
+		logWarnf("SDK comparison unavailable: both runs must complete")
+		return
+	}
+
+	log.Printf("📊 SDK Comparison (latency over %s):", operational.LatencyIncludes)
+	log.Printf("   %-14s %14s %14s %10s", "Metric", SDKTypeOperational, SDKTypeEnterprise, "Change")
+	row := func(name string, a, b float64, unit string) {
+		change := "n/a"
+		if a != 0 {
+			change = fmt.Sprintf("%+.1f%%", (b-a)*100.0/a)
+		}
+		log.Printf("   %-14s %14s %14s %10s", name, fmt.Sprintf("%.2f%s", a, unit), fmt.Sprintf("%.2f%s", b, unit), change)
+	}
+	row("Throughput", operational.ThroughputRPS, enterprise.ThroughputRPS, " RPS")
+	row("Goodput", operational.GoodputRPS, enterprise.GoodputRPS, " RPS")
+	row("Success Rate", operational.SuccessRate, enterprise.SuccessRate, "%")
+	row("p50", operational.Latency.P50Ms, enterprise.Latency.P50Ms, "ms")
+	row("p90", operational.Latency.P90Ms, enterprise.Latency.P90Ms, "ms")
+	row("p95", operational.Latency.P95Ms, enterprise.Latency.P95Ms, "ms")
+	row("p99", operational.Latency.P99Ms, enterprise.Latency.P99Ms, "ms")
+	row("max", operational.Latency.MaxMs, enterprise.Latency.MaxMs, "ms")
+}
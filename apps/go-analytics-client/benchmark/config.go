@@ -0,0 +1,199 @@
+package benchmark
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+)
+
+// unsupportedSettings are environment variables for features neither SDK can
+// provide, with the reason. They are rejected so a run never silently ignores them.
+var unsupportedSettings = map[string]string{
+	"BENCHMARK_USE_PREPARED": "the analytics service does not support prepared statements and gocb.AnalyticsOptions has no Adhoc setting, so every request is parsed and planned",
+}
+
+// LoadConfiguration builds the configuration from field defaults, the YAML file at
+// path (if any), and environment variables, each overriding the one before. Every
+// missing required setting and invalid value is reported in a single error.
+func LoadConfiguration(path string) (Configuration, error) {
+	config := DefaultConfiguration()
+	t := reflect.TypeOf(config)
+	v := reflect.ValueOf(&config).Elem()
+
+	set := make([]bool, t.NumField())
+	if path != "" {
+		present, err := decodeConfigFile(path, &config)
+		if err != nil {
+			return config, err
+		}
+		for i := 0; i < t.NumField(); i++ {
+			set[i] = present[t.Field(i).Tag.Get("yaml")]
+		}
+	}
+
+	var problems []string
+	for i := 0; i < t.NumField(); i++ {
+		name := t.Field(i).Tag.Get("env")
+		value := os.Getenv(name)
+		if name == "" || value == "" {
+			continue
+		}
+		// An invalid value still counts as set so it is not also reported as missing
+		set[i] = true
+		if err := setConfigField(v.Field(i), value); err != nil {
+			problems = append(problems, fmt.Sprintf("invalid value for %s: %q", name, value))
+		}
+	}
+	for name, reason := range unsupportedSettings {
+		if os.Getenv(name) != "" {
+			problems = append(problems, fmt.Sprintf("%s is not supported: %s", name, reason))
+		}
+	}
+
+	var missing []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Tag.Get("required") != "true" {
+			continue
+		}
+		if !set[i] || (field.Type.Kind() == reflect.String && v.Field(i).String() == "") {
+			missing = append(missing, requiredSettingName(field))
+		}
+	}
+	if len(missing) > 0 {
+		problems = append(problems, "missing required settings: "+strings.Join(missing, ", "))
+	}
+
+	if len(problems) > 0 {
+		return config, errors.New(strings.Join(problems, "; "))
+	}
+	return config, nil
+}
+
+// DefaultConfiguration returns a configuration holding only the field defaults.
+// Programs running benchmarks directly start from it and set the required fields.
+func DefaultConfiguration() Configuration {
+	var config Configuration
+	t := reflect.TypeOf(config)
+	v := reflect.ValueOf(&config).Elem()
+
+	for i := 0; i < t.NumField(); i++ {
+		if value, ok := t.Field(i).Tag.Lookup("default"); ok {
+			// The defaults are fixed at compile time, so a bad one is a bug
+			if err := setConfigField(v.Field(i), value); err != nil {
+				panic(fmt.Sprintf("invalid default for %s: %v", t.Field(i).Name, err))
+			}
+		}
+	}
+	return config
+}
+
+// validateRequired reports the required settings left at their zero value, for
+// configurations that were built directly instead of loaded
+func validateRequired(config Configuration) error {
+	t := reflect.TypeOf(config)
+	v := reflect.ValueOf(config)
+
+	var missing []string
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).Tag.Get("required") == "true" && v.Field(i).IsZero() {
+			missing = append(missing, requiredSettingName(t.Field(i)))
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("missing required settings: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// requiredSettingName names a field by its env variable and yaml key
+func requiredSettingName(field reflect.StructField) string {
+	return fmt.Sprintf("%s (%s)", field.Tag.Get("env"), field.Tag.Get("yaml"))
+}
+
+// resolveQuery reads the query from BENCHMARK_QUERY_FILE unless it is set inline,
+// trimming trailing whitespace. One of the two is required.
+func resolveQuery(config *Configuration) error {
+	if config.Query != "" {
+		if config.QueryFile != "" {
+			logWarnf("BENCHMARK_QUERY is set, ignoring BENCHMARK_QUERY_FILE %s", config.QueryFile)
+			config.QueryFile = ""
+		}
+		return nil
+	}
+	if config.QueryFile == "" {
+		return fmt.Errorf("missing required settings: BENCHMARK_QUERY (query) or BENCHMARK_QUERY_FILE (query_file)")
+	}
+
+	data, err := os.ReadFile(config.QueryFile)
+	if err != nil {
+		return fmt.Errorf("failed to read query file: %w", err)
+	}
+	config.Query = strings.TrimRightFunc(string(data), unicode.IsSpace)
+	if strings.TrimSpace(config.Query) == "" {
+		return fmt.Errorf("query file %s is empty", config.QueryFile)
+	}
+	return nil
+}
+
+// decodeConfigFile unmarshals the YAML file into config, rejecting unknown keys,
+// and returns the set of keys the file defines
+func decodeConfigFile(path string, config *Configuration) (map[string]bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(config); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+
+	var keys map[string]interface{}
+	if err := yaml.Unmarshal(data, &keys); err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+	present := make(map[string]bool, len(keys))
+	for key := range keys {
+		present[key] = true
+	}
+	return present, nil
+}
+
+// setConfigField parses value into a string, integer, float or boolean configuration field
+func setConfigField(field reflect.Value, value string) error {
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Int, reflect.Int64:
+		parsed, err := strconv.ParseInt(value, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetInt(parsed)
+	case reflect.Float64:
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return err
+		}
+		field.SetFloat(parsed)
+	case reflect.Bool:
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		field.SetBool(parsed)
+	default:
+		return fmt.Errorf("unsupported configuration field type %s", field.Type())
+	}
+	return nil
+}
@@ -0,0 +1,167 @@
+package benchmark
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const csvFlushInterval = time.Second
+
+// MetricsCSVWriter writes metrics to a CSV file with a header row
+type MetricsCSVWriter struct {
+	outputFile   string
+	resultChan   chan *QueryExecutionMetrics
+	writtenCount int64
+	droppedCount int64
+	done         chan struct{}
+	wg           sync.WaitGroup
+}
+
+// NewMetricsCSVWriter creates a new CSV metrics writer
+func NewMetricsCSVWriter(outputFile string, bufferSize int) *MetricsCSVWriter {
+	return &MetricsCSVWriter{
+		outputFile: outputFile,
+		resultChan: make(chan *QueryExecutionMetrics, bufferSize),
+		done:       make(chan struct{}),
+	}
+}
+
+// WriteResult queues a result for writing
+func (w *MetricsCSVWriter) WriteResult(metrics *QueryExecutionMetrics) {
+	select {
+	case w.resultChan <- metrics:
+	case <-w.done:
+		atomic.AddInt64(&w.droppedCount, 1)
+		logWarnf("Attempted to write to closed metrics writer")
+	default:
+		atomic.AddInt64(&w.droppedCount, 1)
+		logWarnf("Metrics writer queue full, dropping result")
+	}
+}
+
+// Start begins the writer goroutine
+func (w *MetricsCSVWriter) Start(ctx context.Context) {
+	w.wg.Add(1)
+	defer w.wg.Done()
+
+	if err := os.MkdirAll(filepath.Dir(w.outputFile), 0755); err != nil {
+		logErrorf("Failed to create output directory: %v", err)
+		return
+	}
+
+	log.Printf("MetricsCSVWriter starting for file: %s", w.outputFile)
+
+	file, err := os.Create(w.outputFile)
+	if err != nil {
+		logErrorf("Failed to create output file: %v", err)
+		return
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	defer writer.Flush()
+
+	header := make([]string, len(metricsColumns))
+	for i, column := range metricsColumns {
+		header[i] = column.Name
+	}
+	if err := writer.Write(header); err != nil {
+		logErrorf("Failed to write CSV header: %v", err)
+		return
+	}
+
+	write := func(result *QueryExecutionMetrics) {
+		values := metricsValues(result)
+		record := make([]string, len(values))
+		for i, column := range metricsColumns {
+			record[i] = csvValue(column.Kind, values[i])
+		}
+		if err := writer.Write(record); err != nil {
+			logErrorf("Failed to write result: %v", err)
+			return
+		}
+		atomic.AddInt64(&w.writtenCount, 1)
+	}
+
+	ticker := time.NewTicker(csvFlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Printf("MetricsCSVWriter shutting down, draining remaining results...")
+			close(w.done)
+
+			drained := 0
+			for {
+				select {
+				case result := <-w.resultChan:
+					write(result)
+					drained++
+				default:
+					log.Printf("MetricsCSVWriter completed. Total results written: %d (drained %d during shutdown)",
+						atomic.LoadInt64(&w.writtenCount), drained)
+					return
+				}
+			}
+
+		case result := <-w.resultChan:
+			write(result)
+
+		case <-ticker.C:
+			writer.Flush()
+			if err := writer.Error(); err != nil {
+				logErrorf("Failed to flush output file: %v", err)
+			}
+		}
+	}
+}
+
+func (w *MetricsCSVWriter) Wait() {
+	w.wg.Wait()
+}
+
+func (w *MetricsCSVWriter) GetWrittenCount() int64 {
+	return atomic.LoadInt64(&w.writtenCount)
+}
+
+// GetDroppedCount returns the number of results lost to a full queue or a closed writer
+func (w *MetricsCSVWriter) GetDroppedCount() int64 {
+	return atomic.LoadInt64(&w.droppedCount)
+}
+
+func (w *MetricsCSVWriter) GetQueueSize() int {
+	return len(w.resultChan)
+}
+
+// csvValue formats a metrics field for a CSV cell. Composite fields are written
+// as JSON text and unset ones as an empty cell.
+func csvValue(kind reflect.Kind, value interface{}) string {
+	switch kind {
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(reflect.ValueOf(value).Float(), 'f', -1, 64)
+	case reflect.Map, reflect.Slice, reflect.Ptr:
+		if reflect.ValueOf(value).IsNil() {
+			return ""
+		}
+		fallthrough
+	case reflect.Struct:
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return ""
+		}
+		return string(encoded)
+	default:
+		return fmt.Sprint(value)
+	}
+}
@@ -0,0 +1,161 @@
+package benchmark
+
+import (
+	"context"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// degradeHighWater is the queue fill fraction that counts as falling behind
+	degradeHighWater = 0.8
+	// degradeLowWater is the queue fill fraction that counts as recovered
+	degradeLowWater = 0.2
+	// degradeCheckInterval is how often the queue depth is sampled
+	degradeCheckInterval = 100 * time.Millisecond
+)
+
+// DegradingMetricsWriter wraps a MetricsWriter and switches to recording 1-in-K
+// results while the wrapped writer's queue stays above the high-water mark for a
+// sustained period, rather than letting a full queue drop results at random.
+// Full recording resumes once the queue has stayed below the low-water mark for
+// the same period.
+type DegradingMetricsWriter struct {
+	inner       MetricsWriter
+	capacity    int
+	sampleEvery int64
+	sustain     time.Duration
+
+	sampling int32
+	counter  int64
+	skipped  int64
+
+	mu           sync.Mutex
+	sampledTotal time.Duration
+	sampledSince time.Time
+
+	monitor sync.WaitGroup
+}
+
+// NewDegradingMetricsWriter wraps inner, whose queue holds capacity results,
+// sampling 1-in-sampleEvery results while degraded
+func NewDegradingMetricsWriter(inner MetricsWriter, capacity, sampleEvery int, sustain time.Duration) *DegradingMetricsWriter {
+	return &DegradingMetricsWriter{
+		inner:       inner,
+		capacity:    capacity,
+		sampleEvery: int64(sampleEvery),
+		sustain:     sustain,
+	}
+}
+
+// Start runs the queue monitor alongside the wrapped writer
+func (w *DegradingMetricsWriter) Start(ctx context.Context) {
+	w.monitor.Add(1)
+	go w.watchQueue(ctx)
+	w.inner.Start(ctx)
+}
+
+// WriteResult forwards the result, skipping all but 1-in-K while degraded
+func (w *DegradingMetricsWriter) WriteResult(metrics *QueryExecutionMetrics) {
+	if atomic.LoadInt32(&w.sampling) == 1 {
+		if atomic.AddInt64(&w.counter, 1)%w.sampleEvery != 0 {
+			atomic.AddInt64(&w.skipped, 1)
+			return
+		}
+	}
+	w.inner.WriteResult(metrics)
+}
+
+// watchQueue switches between full and sampled recording based on queue depth
+func (w *DegradingMetricsWriter) watchQueue(ctx context.Context) {
+	defer w.monitor.Done()
+
+	ticker := time.NewTicker(degradeCheckInterval)
+	defer ticker.Stop()
+
+	var crossedAt time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			w.setSampling(false, time.Now())
+			return
+		case now := <-ticker.C:
+			fill := float64(w.inner.GetQueueSize()) / float64(w.capacity)
+			sampling := atomic.LoadInt32(&w.sampling) == 1
+
+			// Time how long the queue has been on the far side of the relevant mark
+			crossed := (!sampling && fill >= degradeHighWater) || (sampling && fill <= degradeLowWater)
+			if !crossed {
+				crossedAt = time.Time{}
+				continue
+			}
+			if crossedAt.IsZero() {
+				crossedAt = now
+			}
+			if now.Sub(crossedAt) < w.sustain {
+				continue
+			}
+
+			crossedAt = time.Time{}
+			w.setSampling(!sampling, now)
+			if sampling {
+				log.Printf("Metrics writer caught up (queue %.0f%% full), restoring full recording", fill*100)
+			} else {
+				logWarnf("Metrics writer falling behind (queue %.0f%% full for %v), recording 1 in %d results",
+					fill*100, w.sustain, w.sampleEvery)
+			}
+		}
+	}
+}
+
+// setSampling changes the recording mode and accumulates time spent sampling
+func (w *DegradingMetricsWriter) setSampling(enabled bool, now time.Time) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	wasSampling := atomic.LoadInt32(&w.sampling) == 1
+	switch {
+	case enabled && !wasSampling:
+		w.sampledSince = now
+		atomic.StoreInt32(&w.sampling, 1)
+	case !enabled && wasSampling:
+		w.sampledTotal += now.Sub(w.sampledSince)
+		atomic.StoreInt32(&w.sampling, 0)
+	}
+}
+
+// Wait waits for the wrapped writer and the queue monitor to finish
+func (w *DegradingMetricsWriter) Wait() {
+	w.inner.Wait()
+	w.monitor.Wait()
+}
+
+// GetWrittenCount returns the number of results the wrapped writer wrote
+func (w *DegradingMetricsWriter) GetWrittenCount() int64 {
+	return w.inner.GetWrittenCount()
+}
+
+// GetDroppedCount returns the number of results the wrapped writer dropped.
+// Results skipped while sampling are counted by SkippedCount instead.
+func (w *DegradingMetricsWriter) GetDroppedCount() int64 {
+	return w.inner.GetDroppedCount()
+}
+
+// GetQueueSize returns the wrapped writer's queue depth
+func (w *DegradingMetricsWriter) GetQueueSize() int {
+	return w.inner.GetQueueSize()
+}
+
+// SampledDuration returns how long the writer recorded sampled rather than full results
+func (w *DegradingMetricsWriter) SampledDuration() time.Duration {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.sampledTotal
+}
+
+// SkippedCount returns the number of results not recorded because of sampling
+func (w *DegradingMetricsWriter) SkippedCount() int64 {
+	return atomic.LoadInt64(&w.skipped)
+}
@@ -0,0 +1,426 @@
+package benchmark
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync/atomic"
+	"time"
+
+	cbanalytics "github.com/couchbase/gocbanalytics"
+)
+
+// EnterpriseSDKHandler handles enterprise SDK operations. Requests are spread
+// round-robin over one or more cluster connections.
+type EnterpriseSDKHandler struct {
+	conns           []atomic.Pointer[enterpriseConnection]
+	timings         []ConnectionTiming
+	health          *HealthMonitor
+	keepAlive       *KeepAlive
+	next            uint64
+	endpoint        string
+	queryTimeout    time.Duration
+	sizeSampleEvery int
+	capturePhases   bool
+	countRowsOnly   bool
+	scanConsistency string
+	retry           RetryPolicy
+}
+
+// NewEnterpriseSDKHandler creates a new enterprise SDK handler with
+// config.NumConnections cluster connections
+func NewEnterpriseSDKHandler(config Configuration) (*EnterpriseSDKHandler, error) {
+	retry, err := NewRetryPolicy(config)
+	if err != nil {
+		return nil, err
+	}
+	
+	analyticsURL, err := enterpriseAnalyticsURL(config.ConnectionString, config.AnalyticsPort)
+	if err != nil {
+		return nil, err
+	}
+	
+	handler := &EnterpriseSDKHandler{
+		endpoint:        analyticsURL,
+		queryTimeout:    time.Duration(config.AnalyticsTimeoutS) * time.Second,
+		sizeSampleEvery: config.SizeSampleEvery,
+		capturePhases:   config.CapturePhases,
+		countRowsOnly:   config.CountRowsOnly,
+		scanConsistency: config.ScanConsistency,
+		retry:           retry,
+	}
+	handler.conns = make([]atomic.Pointer[enterpriseConnection], config.NumConnections)
+	for i := range handler.conns {
+		cluster, timing, err := connectEnterpriseCluster(config, analyticsURL)
+		if err != nil {
+			handler.Close()
+			return nil, err
+		}
+		timing.Connection = i
+		handler.timings = append(handler.timings, timing)
+		handler.conns[i].Store(&enterpriseConnection{cluster: cluster, querier: enterpriseQuerierFor(cluster, config)})
+	}
+	
+	if config.HealthcheckIntervalMs > 0 {
+		handler.health = NewHealthMonitor(len(handler.conns),
+			time.Duration(config.HealthcheckIntervalMs)*time.Millisecond,
+			time.Duration(config.ConnectionTimeoutS)*time.Second, config.HealthcheckFailures,
+			func(ctx context.Context, i int) error {
+				return runEnterpriseHealthcheck(ctx, handler.conns[i].Load().cluster, analyticsURL)
+			},
+			func(i int) error {
+				cluster, _, err := connectEnterpriseCluster(config, analyticsURL)
+				if err != nil {
+					return err
+				}
+				old := handler.conns[i].Swap(&enterpriseConnection{cluster: cluster, querier: enterpriseQuerierFor(cluster, config)})
+				old.cluster.Close()
+				return nil
+			})
+		handler.health.Start()
+	}
+	if config.KeepAliveIntervalMs > 0 {
+		handler.keepAlive = NewKeepAlive(len(handler.conns),
+			time.Duration(config.KeepAliveIntervalMs)*time.Millisecond,
+			time.Duration(config.ConnectionTimeoutS)*time.Second,
+			func(ctx context.Context, i int) error {
+				return runEnterpriseHealthcheck(ctx, handler.conns[i].Load().cluster, analyticsURL)
+			})
+		handler.keepAlive.Start()
+	}
+	return handler, nil
+}
+
+// enterpriseConnection is one cluster connection and the querier requests run on
+type enterpriseConnection struct {
+	cluster *cbanalytics.Cluster
+	querier enterpriseQuerier
+}
+
+// connectEnterpriseCluster opens one cluster connection and runs the healthcheck
+// on it, timing both
+func connectEnterpriseCluster(config Configuration, analyticsURL string) (*cbanalytics.Cluster, ConnectionTiming, error) {
+	var timing ConnectionTiming
+	
+	// Create credential
+	credential := cbanalytics.NewBasicAuthCredential(config.Username, config.Password)
+	
+	// Create cluster options
+	opts := cbanalytics.NewClusterOptions().
+		SetTimeoutOptions(cbanalytics.NewTimeoutOptions().
+			SetQueryTimeout(time.Duration(config.AnalyticsTimeoutS) * time.Second).
+			SetConnectTimeout(time.Duration(config.ConnectionTimeoutS) * time.Second))
+	if security := enterpriseSecurityOptions(config); security != nil {
+		opts = opts.SetSecurityOptions(security)
+	}
+	
+	// Connect to cluster
+	start := time.Now()
+	cluster, err := cbanalytics.NewCluster(analyticsURL, credential, opts)
+	if err != nil {
+		return nil, timing, fmt.Errorf("failed to connect to analytics cluster: %w", err)
+	}
+	timing.ConnectMs = float64(time.Since(start).Nanoseconds()) / 1_000_000.0
+	
+	// Test connection
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(config.ConnectionTimeoutS)*time.Second)
+	defer cancel()
+	
+	probeStart := time.Now()
+	err = runEnterpriseHealthcheck(ctx, cluster, analyticsURL)
+	timing.ProbeMs = float64(time.Since(probeStart).Nanoseconds()) / 1_000_000.0
+	timing.ConnectionTimeMs = timing.ConnectMs + timing.ProbeMs
+	if err != nil {
+		if err := applyHealthcheckPolicy(config.HealthcheckPolicy, err); err != nil {
+			cluster.Close()
+			return nil, timing, err
+		}
+	} else {
+		log.Printf("✅ Enterprise SDK connected successfully in %.2fms (test query %.2fms)", timing.ConnectionTimeMs, timing.ProbeMs)
+	}
+	return cluster, timing, nil
+}
+
+// querier returns the cluster, or the query context's scope, of the connection
+// for the next request, round-robin
+func (h *EnterpriseSDKHandler) querier() enterpriseQuerier {
+	return h.conns[(atomic.AddUint64(&h.next, 1)-1)%uint64(len(h.conns))].Load().querier
+}
+
+// runEnterpriseHealthcheck runs the probe query and checks its result
+func runEnterpriseHealthcheck(ctx context.Context, cluster *cbanalytics.Cluster, analyticsURL string) error {
+	testResult, err := cluster.ExecuteQuery(ctx, "SELECT 1 as test")
+	if err != nil {
+		return fmt.Errorf("failed to test analytics connection: %w", err)
+	}
+	
+	// Collect the probe rows so we can check we reached an analytics service
+	var probeRows []json.RawMessage
+	for row := testResult.NextRow(); row != nil; row = testResult.NextRow() {
+		var raw json.RawMessage
+		if err := row.ContentAs(&raw); err == nil {
+			probeRows = append(probeRows, raw)
+		}
+	}
+	
+	if err := testResult.Err(); err != nil {
+		return fmt.Errorf("failed to test analytics connection: %w", err)
+	}
+	if err := checkHealthcheckRows(probeRows); err != nil {
+		return fmt.Errorf("analytics connection test at %s returned an unexpected result: %w", analyticsURL, err)
+	}
+	return nil
+}
+
+// checkHealthcheckRows verifies the connection probe returned exactly {"test": 1}.
+// An empty or different result means the endpoint answered but is not the
+// analytics service we expect, which would otherwise go unnoticed.
+func checkHealthcheckRows(rows []json.RawMessage) error {
+	if len(rows) != 1 {
+		return fmt.Errorf("expected 1 row, got %d", len(rows))
+	}
+	
+	var probe struct {
+		Test *int `json:"test"`
+	}
+	if err := json.Unmarshal(rows[0], &probe); err != nil {
+		return fmt.Errorf("could not decode probe row %s: %w", rows[0], err)
+	}
+	if probe.Test == nil || *probe.Test != 1 {
+		return fmt.Errorf("expected test == 1, got %s", rows[0])
+	}
+	return nil
+}
+
+// ExecuteQuery executes a query using the enterprise SDK
+func (h *EnterpriseSDKHandler) ExecuteQuery(ctx context.Context, query, queryName string, sequenceNumber int64, timeout time.Duration, params QueryParameters) *QueryExecutionMetrics {
+	absoluteStartTimeMs := time.Now().UnixMilli()
+	startTime := time.Now()
+
+	if sequenceNumber <= 10 || sequenceNumber%1000 == 0 {
+		logDebugf("Executing enterprise analytics query #%d", sequenceNumber)
+	}
+	
+	// ✅ FIXED: Use configured timeout, unless the request carries its own
+	if timeout <= 0 {
+		timeout = h.queryTimeout
+	}
+	// Every attempt gets the full timeout; transient failures are retried before
+	// any rows are read
+	querier := h.querier()
+	var result *cbanalytics.QueryResult
+	cancel := func() {}
+	retries, err := h.retry.Do(ctx, func() error {
+		cancel()
+		var attemptCtx context.Context
+		attemptCtx, cancel = context.WithTimeout(ctx, timeout)
+		var err error
+		result, err = querier.ExecuteQuery(attemptCtx, query, enterpriseQueryOptions(params, h.scanConsistency))
+		return err
+	})
+	defer cancel()
+	
+	if err != nil {
+		endTime := time.Now() // Capture end time for errors
+		logWarnf("Enterprise analytics query #%d failed (%d retries): %v", sequenceNumber, retries, err)
+		metrics := NewQueryExecutionMetrics(
+			startTime, endTime, false, err.Error(), 0,
+			"enterprise", queryName, sequenceNumber, absoluteStartTimeMs,
+		)
+		metrics.RetryCount = retries
+		metrics.ErrorCategory = classifyError(err)
+		return metrics
+	}
+	
+	// Count rows, summing raw row sizes for sampled requests. Other rows are
+	// decoded like a real client would, unless only counting them.
+	sampleSize := h.sizeSampleEvery > 0 && sequenceNumber%int64(h.sizeSampleEvery) == 0
+	rowCount := 0
+	var responseBytes int64
+	var firstRow time.Time
+	for row := result.NextRow(); row != nil; row = result.NextRow() {
+		if rowCount == 0 {
+			firstRow = time.Now()
+		}
+		rowCount++
+		if sampleSize {
+			var raw json.RawMessage
+			if err := row.ContentAs(&raw); err == nil {
+				responseBytes += int64(len(raw))
+			}
+			continue
+		}
+		if h.countRowsOnly {
+			continue
+		}
+		var data interface{}
+		row.ContentAs(&data)
+	}
+	
+	// ✅ FIXED: Capture end time AFTER row processing
+	endTime := time.Now()
+	
+	if err := result.Err(); err != nil {
+		logWarnf("Enterprise analytics query #%d row iteration failed: %v", sequenceNumber, err)
+		metrics := NewQueryExecutionMetrics(
+			startTime, endTime, false, err.Error(), rowCount,
+			"enterprise", queryName, sequenceNumber, absoluteStartTimeMs,
+		)
+		metrics.RetryCount = retries
+		metrics.ErrorCategory = classifyError(err)
+		return metrics
+	}
+	
+	metrics := NewQueryExecutionMetrics(
+		startTime, endTime, true, "", rowCount,
+		"enterprise", queryName, sequenceNumber, absoluteStartTimeMs,
+	)
+	metrics.RetryCount = retries
+	metrics.SetFirstRow(startTime, firstRow, endTime)
+	if sampleSize {
+		metrics.RequestBytes = int64(len(query))
+		metrics.ResponseBytes = responseBytes
+	}
+	// Metadata is only available once every row has been read; without it the
+	// server times are left unset
+	if meta, err := result.MetaData(); err == nil {
+		metrics.SetServerTimes(meta.Metrics.ElapsedTime, meta.Metrics.ExecutionTime)
+		if h.capturePhases {
+			metrics.Phases = NewPhaseTimings(endTime.Sub(startTime), meta.Metrics.ElapsedTime, meta.Metrics.ExecutionTime)
+		}
+	}
+	return metrics
+}
+
+// enterpriseQueryOptions sets the request's parameters and the scan consistency,
+// when one is configured, on the query options
+func enterpriseQueryOptions(params QueryParameters, scanConsistency string) *cbanalytics.QueryOptions {
+	opts := cbanalytics.NewQueryOptions()
+	if len(params.Positional) > 0 {
+		opts = opts.SetPositionalParameters(params.Positional)
+	}
+	if len(params.Named) > 0 {
+		opts = opts.SetNamedParameters(params.Named)
+	}
+	switch scanConsistency {
+	case ScanConsistencyNotBounded:
+		opts = opts.SetScanConsistency(cbanalytics.QueryScanConsistencyNotBounded)
+	case ScanConsistencyRequestPlus:
+		opts = opts.SetScanConsistency(cbanalytics.QueryScanConsistencyRequestPlus)
+	}
+	return opts
+}
+
+// FetchRows executes a query and returns its raw rows
+func (h *EnterpriseSDKHandler) FetchRows(query string) ([]json.RawMessage, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), h.queryTimeout)
+	defer cancel()
+	
+	result, err := h.querier().ExecuteQuery(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	
+	var rows []json.RawMessage
+	for row := result.NextRow(); row != nil; row = result.NextRow() {
+		var raw json.RawMessage
+		if err := row.ContentAs(&raw); err != nil {
+			return nil, err
+		}
+		rows = append(rows, raw)
+	}
+	
+	if err := result.Err(); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// TraceQuery executes a query capturing all the metadata gocbanalytics exposes.
+// The SDK talks to a single endpoint, which is recorded as the node.
+func (h *EnterpriseSDKHandler) TraceQuery(query string, params QueryParameters, canary int64) *CanaryTrace {
+	trace := &CanaryTrace{
+		TimestampMs: time.Now().UnixMilli(),
+		SDKType:     "enterprise",
+		Query:       query,
+		Node:        h.endpoint,
+	}
+	
+	ctx, cancel := context.WithTimeout(context.Background(), h.queryTimeout)
+	defer cancel()
+	
+	startTime := time.Now()
+	result, err := h.querier().ExecuteQuery(ctx, query, enterpriseQueryOptions(params, h.scanConsistency))
+	if err != nil {
+		trace.DurationMs = float64(time.Since(startTime).Nanoseconds()) / 1_000_000.0
+		trace.ErrorMessage = err.Error()
+		return trace
+	}
+	
+	for row := result.NextRow(); row != nil; row = result.NextRow() {
+		if trace.RowCount == 0 {
+			trace.TimeToFirstRowMs = float64(time.Since(startTime).Nanoseconds()) / 1_000_000.0
+		}
+		trace.RowCount++
+		var raw json.RawMessage
+		if err := row.ContentAs(&raw); err == nil {
+			trace.ResponseBytes += int64(len(raw))
+		}
+	}
+	endTime := time.Now()
+	trace.DurationMs = float64(endTime.Sub(startTime).Nanoseconds()) / 1_000_000.0
+	
+	if err := result.Err(); err != nil {
+		trace.ErrorMessage = err.Error()
+		return trace
+	}
+	trace.Success = true
+	
+	if meta, err := result.MetaData(); err == nil {
+		trace.RequestID = meta.RequestID
+		trace.ServerElapsedMs = float64(meta.Metrics.ElapsedTime.Nanoseconds()) / 1_000_000.0
+		trace.ServerExecutionMs = float64(meta.Metrics.ExecutionTime.Nanoseconds()) / 1_000_000.0
+		trace.ResultCount = meta.Metrics.ResultCount
+		trace.ResultSize = meta.Metrics.ResultSize
+		trace.ProcessedObjects = meta.Metrics.ProcessedObjects
+		for _, warning := range meta.Warnings {
+			trace.Warnings = append(trace.Warnings, fmt.Sprintf("%d: %s", warning.Code, warning.Message))
+		}
+		trace.Phases = NewPhaseTimings(endTime.Sub(startTime), meta.Metrics.ElapsedTime, meta.Metrics.ExecutionTime)
+	}
+	return trace
+}
+
+// GetSDKType returns the SDK type
+func (h *EnterpriseSDKHandler) GetSDKType() string {
+	return "enterprise"
+}
+
+// ConnectionTimings returns how long opening each initial connection took
+func (h *EnterpriseSDKHandler) ConnectionTimings() []ConnectionTiming {
+	return h.timings
+}
+
+// HealthReconnects returns the connections replaced after failing health checks
+func (h *EnterpriseSDKHandler) HealthReconnects() []HealthReconnectEvent {
+	return h.health.Events()
+}
+
+// Close stops the health monitor and keepalive and closes every cluster connection
+func (h *EnterpriseSDKHandler) Close() error {
+	h.health.Close()
+	h.keepAlive.Close()
+	var errs []error
+	for i := range h.conns {
+		conn := h.conns[i].Load()
+		if conn == nil {
+			continue
+		}
+		if err := conn.cluster.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
\ No newline at end of file
@@ -0,0 +1,38 @@
+package benchmark
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestCheckHealthcheckRows(t *testing.T) {
+	tests := []struct {
+		name    string
+		rows    []string
+		wantErr bool
+	}{
+		{name: "expected row", rows: []string{`{"test":1}`}},
+		{name: "no rows", rows: nil, wantErr: true},
+		{name: "two rows", rows: []string{`{"test":1}`, `{"test":1}`}, wantErr: true},
+		{name: "wrong value", rows: []string{`{"test":2}`}, wantErr: true},
+		{name: "missing key", rows: []string{`{"other":1}`}, wantErr: true},
+		{name: "malformed JSON", rows: []string{`{"test":`}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows := make([]json.RawMessage, 0, len(tt.rows))
+			for _, row := range tt.rows {
+				rows = append(rows, json.RawMessage(row))
+			}
+
+			err := checkHealthcheckRows(rows)
+			if tt.wantErr && err == nil {
+				t.Fatalf("checkHealthcheckRows(%v) succeeded, want an error", tt.rows)
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatalf("checkHealthcheckRows(%v) = %v, want nil", tt.rows, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package benchmark
+
+import (
+	"context"
+	"errors"
+	"net"
+
+	"github.com/couchbase/gocb/v2"
+	cbanalytics "github.com/couchbase/gocbanalytics"
+)
+
+// Error categories recorded on failed requests
+const (
+	ErrorCategoryTimeout    = "timeout"
+	ErrorCategoryConnection = "connection"
+	ErrorCategorySyntax     = "syntax"
+	ErrorCategoryAuth       = "auth"
+	ErrorCategoryOther      = "other"
+	// ErrorCategoryCanceled marks a request abandoned because the run was stopped
+	ErrorCategoryCanceled = "canceled"
+	// ErrorCategoryValidation marks a request that succeeded with an unexpected result
+	ErrorCategoryValidation = "validation"
+)
+
+// Analytics reports parse and compilation failures with codes in this range
+const (
+	analyticsCompilationCodeMin = 24000
+	analyticsCompilationCodeMax = 24999
+)
+
+var errorCategories = []struct {
+	category string
+	errs     []error
+}{
+	{ErrorCategoryCanceled, []error{context.Canceled, gocb.ErrRequestCanceled}},
+	{ErrorCategoryTimeout, []error{context.DeadlineExceeded, gocb.ErrTimeout, gocb.ErrUnambiguousTimeout,
+		gocb.ErrAmbiguousTimeout, cbanalytics.ErrTimeout}},
+	{ErrorCategoryAuth, []error{gocb.ErrAuthenticationFailure, cbanalytics.ErrInvalidCredential}},
+	{ErrorCategorySyntax, []error{gocb.ErrParsingFailure, gocb.ErrCompilationFailure}},
+	{ErrorCategoryConnection, []error{gocb.ErrServiceNotAvailable, cbanalytics.ErrServiceUnavailable, cbanalytics.ErrClosed}},
+}
+
+// classifyError maps a failed request's error onto a coarse category using the
+// SDK error types, so failures can be counted without matching messages
+func classifyError(err error) string {
+	for _, group := range errorCategories {
+		for _, target := range group.errs {
+			if errors.Is(err, target) {
+				return group.category
+			}
+		}
+	}
+
+	var queryErr cbanalytics.QueryError
+	if errors.As(err, &queryErr) && queryErr.Code >= analyticsCompilationCodeMin && queryErr.Code <= analyticsCompilationCodeMax {
+		return ErrorCategorySyntax
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		if netErr.Timeout() {
+			return ErrorCategoryTimeout
+		}
+		return ErrorCategoryConnection
+	}
+	return ErrorCategoryOther
+}
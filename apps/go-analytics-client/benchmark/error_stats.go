@@ -0,0 +1,122 @@
+package benchmark
+
+import (
+	"regexp"
+	"sort"
+	"sync"
+)
+
+const (
+	// errorStatsCapacity bounds the distinct normalized errors tracked at once
+	errorStatsCapacity = 100
+	// topErrorsReported is how many error kinds the summary lists
+	topErrorsReported = 10
+)
+
+// Patterns for the variable parts of error messages, applied in order
+var errorNormalizers = []struct {
+	pattern     *regexp.Regexp
+	replacement string
+}{
+	{regexp.MustCompile(`[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`), "<uuid>"},
+	{regexp.MustCompile(`0x[0-9a-fA-F]+`), "<hex>"},
+	{regexp.MustCompile(`\b[0-9a-fA-F]*[0-9][0-9a-fA-F]*[a-fA-F][0-9a-fA-F]*\b|\b[0-9a-fA-F]*[a-fA-F][0-9a-fA-F]*[0-9][0-9a-fA-F]*\b`), "<id>"},
+	{regexp.MustCompile(`\d+(\.\d+)?`), "<n>"},
+}
+
+// normalizeErrorMessage strips IDs and numbers so equivalent errors count together
+func normalizeErrorMessage(message string) string {
+	for _, normalizer := range errorNormalizers {
+		message = normalizer.pattern.ReplaceAllString(message, normalizer.replacement)
+	}
+	return message
+}
+
+// ErrorCount is a normalized error message and how often it occurred
+type ErrorCount struct {
+	Message string
+	Count   int64
+}
+
+// ErrorStats counts normalized error messages with bounded memory using the
+// Space-Saving algorithm: once full, a new message evicts the least frequent one
+// and inherits its count, so counts of rare messages may be overestimated but
+// frequent ones are always retained.
+// Categories are few and fixed, so they are counted exactly.
+type ErrorStats struct {
+	mu         sync.Mutex
+	counts     map[string]int64
+	categories map[string]int64
+	total      int64
+}
+
+// NewErrorStats creates an empty error aggregator
+func NewErrorStats() *ErrorStats {
+	return &ErrorStats{counts: make(map[string]int64), categories: make(map[string]int64)}
+}
+
+// Add records one failure message and its category
+func (s *ErrorStats) Add(message, category string) {
+	key := normalizeErrorMessage(message)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.total++
+	if category != "" {
+		s.categories[category]++
+	}
+	if _, ok := s.counts[key]; ok || len(s.counts) < errorStatsCapacity {
+		s.counts[key]++
+		return
+	}
+
+	minKey, minCount := "", int64(-1)
+	for existing, count := range s.counts {
+		if minCount < 0 || count < minCount {
+			minKey, minCount = existing, count
+		}
+	}
+	delete(s.counts, minKey)
+	s.counts[key] = minCount + 1
+}
+
+// Top returns up to n most frequent normalized errors
+func (s *ErrorStats) Top(n int) []ErrorCount {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	errors := make([]ErrorCount, 0, len(s.counts))
+	for message, count := range s.counts {
+		errors = append(errors, ErrorCount{Message: message, Count: count})
+	}
+	sort.Slice(errors, func(i, j int) bool {
+		if errors[i].Count != errors[j].Count {
+			return errors[i].Count > errors[j].Count
+		}
+		return errors[i].Message < errors[j].Message
+	})
+	if len(errors) > n {
+		errors = errors[:n]
+	}
+	return errors
+}
+
+// Categories returns the number of failures in each error category
+func (s *ErrorStats) Categories() map[string]int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	categories := make(map[string]int64, len(s.categories))
+	for category, count := range s.categories {
+		categories[category] = count
+	}
+	return categories
+}
+
+// Total returns the number of recorded failures
+func (s *ErrorStats) Total() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.total
+}
@@ -0,0 +1,51 @@
+package benchmark
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// sensitiveHeaderMarkers identify header names whose values must not be logged
+var sensitiveHeaderMarkers = []string{"auth", "token", "key", "secret", "cookie", "password"}
+
+// ParseExtraHeaders parses a comma-separated list of "name:value" headers
+func ParseExtraHeaders(spec string) (map[string]string, error) {
+	headers := make(map[string]string)
+	if strings.TrimSpace(spec) == "" {
+		return headers, nil
+	}
+
+	for _, entry := range strings.Split(spec, ",") {
+		name, value, ok := strings.Cut(entry, ":")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid header %q (expected name:value)", entry)
+		}
+		headers[name] = strings.TrimSpace(value)
+	}
+	return headers, nil
+}
+
+// RedactedHeaders renders headers for logging with sensitive values masked
+func RedactedHeaders(headers map[string]string) string {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		value := headers[name]
+		lower := strings.ToLower(name)
+		for _, marker := range sensitiveHeaderMarkers {
+			if strings.Contains(lower, marker) {
+				value = "<redacted>"
+				break
+			}
+		}
+		parts = append(parts, fmt.Sprintf("%s: %s", name, value))
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,144 @@
+package benchmark
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// HealthReconnectEvent records a connection replaced after failing consecutive health checks
+type HealthReconnectEvent struct {
+	Cluster     string  `json:"cluster,omitempty"`
+	TimestampMs int64   `json:"timestamp_ms"`
+	Connection  int     `json:"connection"`
+	Failures    int     `json:"failures"`
+	LastError   string  `json:"last_error"`
+	ReconnectMs float64 `json:"reconnect_ms"`
+	// Error is set when opening the replacement connection failed; the old
+	// connection is kept and the reconnect is retried after the next failed check
+	Error string `json:"error,omitempty"`
+}
+
+// healthReporter is implemented by handlers that monitor their connections
+type healthReporter interface {
+	HealthReconnects() []HealthReconnectEvent
+}
+
+// HealthMonitor probes each of a handler's connections on an interval and
+// reconnects one that fails threshold probes in a row, so a dropped connection
+// costs a brief error window instead of failing every request until restart
+type HealthMonitor struct {
+	interval  time.Duration
+	timeout   time.Duration
+	threshold int
+	probe     func(ctx context.Context, connection int) error
+	reconnect func(connection int) error
+	failures  []int
+
+	mu     sync.Mutex
+	events []HealthReconnectEvent
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewHealthMonitor creates a monitor for the given number of connections. probe
+// checks one connection and reconnect replaces it.
+func NewHealthMonitor(connections int, interval, timeout time.Duration, threshold int,
+	probe func(ctx context.Context, connection int) error, reconnect func(connection int) error) *HealthMonitor {
+	return &HealthMonitor{
+		interval:  interval,
+		timeout:   timeout,
+		threshold: threshold,
+		probe:     probe,
+		reconnect: reconnect,
+		failures:  make([]int, connections),
+		done:      make(chan struct{}),
+	}
+}
+
+// Start probes the connections in the background until Close
+func (m *HealthMonitor) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	m.cancel = cancel
+	go m.run(ctx)
+}
+
+func (m *HealthMonitor) run(ctx context.Context) {
+	defer close(m.done)
+
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for connection := range m.failures {
+				m.check(ctx, connection)
+			}
+		}
+	}
+}
+
+// check probes one connection and reconnects it once the failure threshold is reached
+func (m *HealthMonitor) check(ctx context.Context, connection int) {
+	probeCtx, cancel := context.WithTimeout(ctx, m.timeout)
+	err := m.probe(probeCtx, connection)
+	cancel()
+	if err == nil {
+		m.failures[connection] = 0
+		return
+	}
+	if ctx.Err() != nil {
+		return
+	}
+
+	m.failures[connection]++
+	logWarnf("Health check %d/%d failed on connection %d: %v", m.failures[connection], m.threshold, connection, err)
+	if m.failures[connection] < m.threshold {
+		return
+	}
+
+	start := time.Now()
+	event := HealthReconnectEvent{
+		TimestampMs: start.UnixMilli(),
+		Connection:  connection,
+		Failures:    m.failures[connection],
+		LastError:   err.Error(),
+	}
+	if err := m.reconnect(connection); err != nil {
+		logErrorf("Failed to reconnect connection %d: %v", connection, err)
+		event.Error = err.Error()
+	} else {
+		log.Printf("🔌 Reconnected connection %d after %d failed health checks", connection, event.Failures)
+		m.failures[connection] = 0
+	}
+	event.ReconnectMs = float64(time.Since(start).Nanoseconds()) / 1_000_000.0
+
+	m.mu.Lock()
+	m.events = append(m.events, event)
+	m.mu.Unlock()
+}
+
+// Events returns the reconnects so far. It is safe to call on a nil monitor.
+func (m *HealthMonitor) Events() []HealthReconnectEvent {
+	if m == nil {
+		return nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]HealthReconnectEvent(nil), m.events...)
+}
+
+// Close stops probing and waits for a reconnect in progress. It is safe to call
+// on a nil monitor.
+func (m *HealthMonitor) Close() {
+	if m == nil || m.cancel == nil {
+		return
+	}
+	m.cancel()
+	<-m.done
+}
@@ -0,0 +1,179 @@
+package benchmark
+
+import (
+	"context"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	hdrhistogram "github.com/HdrHistogram/hdrhistogram-go"
+)
+
+// Interval histograms record latencies in nanoseconds, the unit HdrHistogram log
+// tools assume by default; longer latencies are clamped to the upper bound
+const (
+	histogramLowestNanos  = 1
+	histogramHighestNanos = int64(time.Hour)
+)
+
+// MetricsHistogramWriter aggregates latencies and writes one HdrHistogram interval
+// histogram per interval, in the standard histogram log format, instead of
+// per-request records. Successful requests are tagged with the SDK type and failed
+// ones with the SDK type plus "-failed", so logs from several intervals, runs or
+// instances can be merged with the HdrHistogram log tools.
+type MetricsHistogramWriter struct {
+	outputFile string
+	interval   time.Duration
+
+	mu            sync.Mutex
+	successes     *hdrhistogram.Histogram
+	failures      *hdrhistogram.Histogram
+	sdkType       string
+	intervalStart time.Time
+
+	recordedCount int64
+	wg            sync.WaitGroup
+}
+
+// NewMetricsHistogramWriter creates a writer emitting an interval histogram every
+// interval, with the given number of significant digits
+func NewMetricsHistogramWriter(outputFile string, interval time.Duration, significantDigits int) *MetricsHistogramWriter {
+	return &MetricsHistogramWriter{
+		outputFile:    outputFile,
+		interval:      interval,
+		successes:     hdrhistogram.New(histogramLowestNanos, histogramHighestNanos, significantDigits),
+		failures:      hdrhistogram.New(histogramLowestNanos, histogramHighestNanos, significantDigits),
+		intervalStart: time.Now(),
+	}
+}
+
+// WriteResult adds a result to the current interval's histograms
+func (w *MetricsHistogramWriter) WriteResult(metrics *QueryExecutionMetrics) {
+	nanos := metrics.DurationNanos
+	if nanos < histogramLowestNanos {
+		nanos = histogramLowestNanos
+	}
+	if nanos > histogramHighestNanos {
+		nanos = histogramHighestNanos
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.sdkType = metrics.SDKType
+	if metrics.Success {
+		w.successes.RecordValue(nanos)
+	} else {
+		w.failures.RecordValue(nanos)
+	}
+	atomic.AddInt64(&w.recordedCount, 1)
+}
+
+// Start writes an interval histogram every interval until ctx is cancelled, then
+// writes the final one
+func (w *MetricsHistogramWriter) Start(ctx context.Context) {
+	w.wg.Add(1)
+	defer w.wg.Done()
+
+	if err := os.MkdirAll(filepath.Dir(w.outputFile), 0755); err != nil {
+		logErrorf("Failed to create output directory: %v", err)
+		return
+	}
+
+	log.Printf("MetricsHistogramWriter starting for file: %s (interval histogram every %v)", w.outputFile, w.interval)
+
+	file, err := os.Create(w.outputFile)
+	if err != nil {
+		logErrorf("Failed to create output file: %v", err)
+		return
+	}
+	defer file.Close()
+
+	logWriter := hdrhistogram.NewHistogramLogWriter(file)
+	if err := w.writeHeader(logWriter); err != nil {
+		logErrorf("Failed to write histogram log header: %v", err)
+		return
+	}
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			if err := w.writeInterval(logWriter); err != nil {
+				logErrorf("Failed to write final interval histogram: %v", err)
+			}
+			log.Printf("MetricsHistogramWriter completed. Total results aggregated: %d", atomic.LoadInt64(&w.recordedCount))
+			return
+		case <-ticker.C:
+			if err := w.writeInterval(logWriter); err != nil {
+				logErrorf("Failed to write interval histogram: %v", err)
+			}
+		}
+	}
+}
+
+// writeHeader writes the log format version, start time and column legend
+func (w *MetricsHistogramWriter) writeHeader(logWriter *hdrhistogram.HistogramLogWriter) error {
+	if err := logWriter.OutputLogFormatVersion(); err != nil {
+		return err
+	}
+	if err := logWriter.OutputStartTime(w.intervalStart.UnixMilli()); err != nil {
+		return err
+	}
+	return logWriter.OutputLegend()
+}
+
+// writeInterval writes the current interval's histograms and starts a new
+// interval. The failure histogram is only written for intervals with failures.
+func (w *MetricsHistogramWriter) writeInterval(logWriter *hdrhistogram.HistogramLogWriter) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	now := time.Now()
+	for _, interval := range []struct {
+		histogram *hdrhistogram.Histogram
+		tag       string
+	}{
+		{w.successes, w.sdkType},
+		{w.failures, w.sdkType + "-failed"},
+	} {
+		if interval.histogram == w.failures && interval.histogram.TotalCount() == 0 {
+			continue
+		}
+		interval.histogram.SetTag(interval.tag)
+		interval.histogram.SetStartTimeMs(w.intervalStart.UnixMilli())
+		interval.histogram.SetEndTimeMs(now.UnixMilli())
+		if err := logWriter.OutputIntervalHistogram(interval.histogram); err != nil {
+			return err
+		}
+	}
+
+	w.successes.Reset()
+	w.failures.Reset()
+	w.intervalStart = now
+	return nil
+}
+
+func (w *MetricsHistogramWriter) Wait() {
+	w.wg.Wait()
+}
+
+// GetWrittenCount returns the number of results aggregated into interval histograms
+func (w *MetricsHistogramWriter) GetWrittenCount() int64 {
+	return atomic.LoadInt64(&w.recordedCount)
+}
+
+// GetDroppedCount is always zero since results are aggregated as they arrive
+func (w *MetricsHistogramWriter) GetDroppedCount() int64 {
+	return 0
+}
+
+// GetQueueSize is always zero since results are aggregated as they arrive
+func (w *MetricsHistogramWriter) GetQueueSize() int {
+	return 0
+}
@@ -0,0 +1,204 @@
+package benchmark
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	influxMeasurement  = "analytics_query"
+	influxWriteTimeout = 10 * time.Second
+)
+
+// influxTagEscaper escapes the characters line protocol reserves in tag values
+var influxTagEscaper = strings.NewReplacer(",", `\,`, " ", `\ `, "=", `\=`)
+
+// InfluxWriter sends every result to an InfluxDB v2 bucket as line protocol.
+// Results are queued without blocking the workers and written in batches, flushed
+// when a batch fills or the flush interval passes. A failed write drops its batch
+// and costs a log line per outage.
+type InfluxWriter struct {
+	writeURL      string
+	token         string
+	batchSize     int
+	flushInterval time.Duration
+	client        *http.Client
+
+	resultChan   chan *QueryExecutionMetrics
+	writtenCount int64
+	droppedCount int64
+	done         chan struct{}
+	stopped      chan struct{}
+}
+
+// NewInfluxWriter creates a writer for the bucket in org at the InfluxDB server baseURL
+func NewInfluxWriter(baseURL, org, bucket, token string, batchSize int, flushInterval time.Duration) *InfluxWriter {
+	query := url.Values{}
+	query.Set("org", org)
+	query.Set("bucket", bucket)
+	query.Set("precision", "ms")
+
+	return &InfluxWriter{
+		writeURL:      strings.TrimSuffix(baseURL, "/") + "/api/v2/write?" + query.Encode(),
+		token:         token,
+		batchSize:     batchSize,
+		flushInterval: flushInterval,
+		client:        &http.Client{Timeout: influxWriteTimeout},
+		resultChan:    make(chan *QueryExecutionMetrics, metricsQueueCapacity),
+		done:          make(chan struct{}),
+		stopped:       make(chan struct{}),
+	}
+}
+
+// WriteResult queues a result for the next batch
+func (w *InfluxWriter) WriteResult(metrics *QueryExecutionMetrics) {
+	select {
+	case w.resultChan <- metrics:
+	case <-w.done:
+		atomic.AddInt64(&w.droppedCount, 1)
+		logWarnf("Attempted to write to closed InfluxDB writer")
+	default:
+		atomic.AddInt64(&w.droppedCount, 1)
+		logWarnf("InfluxDB writer queue full, dropping result")
+	}
+}
+
+// Start batches queued results until ctx is cancelled, then drains the queue and
+// writes the final batch
+func (w *InfluxWriter) Start(ctx context.Context) {
+	defer close(w.stopped)
+
+	ticker := time.NewTicker(w.flushInterval)
+	defer ticker.Stop()
+
+	var batch bytes.Buffer
+	lines := 0
+	failing := false
+	flush := func() {
+		if lines == 0 {
+			return
+		}
+		err := w.post(batch.Bytes())
+		switch {
+		case err != nil && !failing:
+			logWarnf("InfluxDB write failed, dropping batches until it recovers: %v", err)
+			failing = true
+		case err == nil:
+			if failing {
+				log.Printf("InfluxDB write recovered")
+				failing = false
+			}
+			atomic.AddInt64(&w.writtenCount, int64(lines))
+		}
+		batch.Reset()
+		lines = 0
+	}
+	add := func(result *QueryExecutionMetrics) {
+		batch.WriteString(influxLine(result))
+		lines++
+		if lines >= w.batchSize {
+			flush()
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			close(w.done)
+			for {
+				select {
+				case result := <-w.resultChan:
+					add(result)
+				default:
+					flush()
+					return
+				}
+			}
+		case result := <-w.resultChan:
+			add(result)
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+// post writes one batch of lines to the server
+func (w *InfluxWriter) post(body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, w.writeURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Token "+w.token)
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(message)))
+	}
+	return nil
+}
+
+// influxLine formats a result as one line of line protocol, timestamped with the
+// request's start time in milliseconds
+func influxLine(metrics *QueryExecutionMetrics) string {
+	var line strings.Builder
+	line.WriteString(influxMeasurement)
+	line.WriteString(",sdk=")
+	line.WriteString(influxTagEscaper.Replace(metrics.SDKType))
+	// Empty tag values are not allowed in line protocol
+	if metrics.QueryName != "" {
+		line.WriteString(",query=")
+		line.WriteString(influxTagEscaper.Replace(metrics.QueryName))
+	}
+	if metrics.ErrorCategory != "" {
+		line.WriteString(",error_category=")
+		line.WriteString(influxTagEscaper.Replace(metrics.ErrorCategory))
+	}
+
+	line.WriteString(" duration_ms=")
+	line.WriteString(strconv.FormatFloat(metrics.DurationMs, 'f', -1, 64))
+	line.WriteString(",success=")
+	line.WriteString(strconv.FormatBool(metrics.Success))
+	line.WriteString(",row_count=")
+	line.WriteString(strconv.Itoa(metrics.RowCount))
+	line.WriteString("i,sequence_number=")
+	line.WriteString(strconv.FormatInt(metrics.SequenceNumber, 10))
+	line.WriteString("i,retry_count=")
+	line.WriteString(strconv.Itoa(metrics.RetryCount))
+	line.WriteString("i ")
+	line.WriteString(strconv.FormatInt(metrics.AbsoluteStartTimeMs, 10))
+	line.WriteString("\n")
+	return line.String()
+}
+
+func (w *InfluxWriter) Wait() {
+	<-w.stopped
+}
+
+// GetWrittenCount returns the number of results the server accepted
+func (w *InfluxWriter) GetWrittenCount() int64 {
+	return atomic.LoadInt64(&w.writtenCount)
+}
+
+// GetDroppedCount returns the number of results lost to a full queue or a closed writer
+func (w *InfluxWriter) GetDroppedCount() int64 {
+	return atomic.LoadInt64(&w.droppedCount)
+}
+
+func (w *InfluxWriter) GetQueueSize() int {
+	return len(w.resultChan)
+}
@@ -0,0 +1,89 @@
+package benchmark
+
+import (
+	"context"
+	"log"
+	"sync/atomic"
+	"time"
+)
+
+// KeepAlive sends a trickle of probe queries on each of a handler's connections,
+// so a server that tears down idle connections does not do so between bursts of
+// load. The probes bypass ExecuteQuery and never reach the run's metrics.
+type KeepAlive struct {
+	connections int
+	interval    time.Duration
+	timeout     time.Duration
+	probe       func(ctx context.Context, connection int) error
+
+	sent   int64
+	failed int64
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewKeepAlive creates a keepalive for the given number of connections. probe
+// runs one keepalive query on a connection.
+func NewKeepAlive(connections int, interval, timeout time.Duration, probe func(ctx context.Context, connection int) error) *KeepAlive {
+	return &KeepAlive{
+		connections: connections,
+		interval:    interval,
+		timeout:     timeout,
+		probe:       probe,
+		done:        make(chan struct{}),
+	}
+}
+
+// Start sends keepalives in the background until Close
+func (k *KeepAlive) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	k.cancel = cancel
+	go k.run(ctx)
+}
+
+func (k *KeepAlive) run(ctx context.Context) {
+	defer close(k.done)
+
+	ticker := time.NewTicker(k.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for connection := 0; connection < k.connections; connection++ {
+				k.send(ctx, connection)
+			}
+		}
+	}
+}
+
+// send runs one keepalive query, logging a failure at debug level; the health
+// monitor, when enabled, is what acts on a dead connection
+func (k *KeepAlive) send(ctx context.Context, connection int) {
+	probeCtx, cancel := context.WithTimeout(ctx, k.timeout)
+	err := k.probe(probeCtx, connection)
+	cancel()
+	if ctx.Err() != nil {
+		return
+	}
+	atomic.AddInt64(&k.sent, 1)
+	if err != nil {
+		atomic.AddInt64(&k.failed, 1)
+		logDebugf("Keepalive failed on connection %d: %v", connection, err)
+	}
+}
+
+// Close stops the keepalives and logs how many were sent. It is safe to call on
+// a nil keepalive.
+func (k *KeepAlive) Close() {
+	if k == nil || k.cancel == nil {
+		return
+	}
+	k.cancel()
+	<-k.done
+	log.Printf("Keepalive stopped: %d queries sent, %d failed (excluded from metrics)",
+		atomic.LoadInt64(&k.sent), atomic.LoadInt64(&k.failed))
+}
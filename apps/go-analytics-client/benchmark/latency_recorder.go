@@ -0,0 +1,114 @@
+package benchmark
+
+import (
+	"fmt"
+	"math"
+	"sync"
+
+	hdrhistogram "github.com/HdrHistogram/hdrhistogram-go"
+)
+
+// Latencies are recorded in microseconds between these bounds; longer ones are
+// clamped to the upper bound
+const (
+	latencyRecorderLowestMicros  = 1
+	latencyRecorderHighestMicros = int64(3600 * 1_000_000)
+)
+
+// validateSignificantDigits checks the HdrHistogram precision setting
+func validateSignificantDigits(digits int) error {
+	if digits < 1 || digits > 5 {
+		return fmt.Errorf("BENCHMARK_LATENCY_SIGNIFICANT_DIGITS must be between 1 and 5, got %d", digits)
+	}
+	return nil
+}
+
+// LatencyRecorder records latencies in an HdrHistogram, so memory stays bounded
+// however many requests a run makes. Percentiles are accurate to the configured
+// number of significant digits. It is safe for concurrent use.
+type LatencyRecorder struct {
+	mu        sync.Mutex
+	histogram *hdrhistogram.Histogram
+	digits    int
+}
+
+// NewLatencyRecorder creates an empty recorder with the given precision
+func NewLatencyRecorder(significantDigits int) *LatencyRecorder {
+	return &LatencyRecorder{
+		histogram: hdrhistogram.New(latencyRecorderLowestMicros, latencyRecorderHighestMicros, significantDigits),
+		digits:    significantDigits,
+	}
+}
+
+// Record adds one latency in milliseconds
+func (r *LatencyRecorder) Record(latencyMs float64) {
+	micros := latencyMicros(latencyMs)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.histogram.RecordValue(micros)
+}
+
+// RecordCorrected adds one latency in milliseconds, corrected for coordinated
+// omission: a latency longer than the expected interval between requests held
+// back the requests scheduled behind it, so samples for those are synthesized
+// as well, each one interval shorter than the last, down to the interval
+func (r *LatencyRecorder) RecordCorrected(latencyMs, expectedIntervalMs float64) {
+	micros := latencyMicros(latencyMs)
+	interval := int64(math.Round(expectedIntervalMs * 1000))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.histogram.RecordCorrectedValue(micros, interval)
+}
+
+// Count returns the number of recorded latencies
+func (r *LatencyRecorder) Count() int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.histogram.TotalCount()
+}
+
+// Percentiles returns each requested percentile (0-100) in milliseconds; 100 is
+// the maximum. Every percentile of an empty recorder is zero.
+func (r *LatencyRecorder) Percentiles(percentiles ...float64) map[float64]float64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	result := make(map[float64]float64, len(percentiles))
+	for _, p := range percentiles {
+		var micros int64
+		switch {
+		case r.histogram.TotalCount() == 0:
+		case p >= 100:
+			micros = r.histogram.Max()
+		default:
+			micros = r.histogram.ValueAtQuantile(p)
+		}
+		result[p] = float64(micros) / 1000.0
+	}
+	return result
+}
+
+// latencyMicros converts a latency to whole microseconds within the recorder bounds
+func latencyMicros(latencyMs float64) int64 {
+	micros := int64(math.Round(latencyMs * 1000))
+	if micros < latencyRecorderLowestMicros {
+		return latencyRecorderLowestMicros
+	}
+	if micros > latencyRecorderHighestMicros {
+		return latencyRecorderHighestMicros
+	}
+	return micros
+}
+
+// Merged returns a new recorder holding the latencies of r and other
+func (r *LatencyRecorder) Merged(other *LatencyRecorder) *LatencyRecorder {
+	merged := NewLatencyRecorder(r.digits)
+	for _, source := range []*LatencyRecorder{r, other} {
+		source.mu.Lock()
+		merged.histogram.Merge(source.histogram)
+		source.mu.Unlock()
+	}
+	return merged
+}
@@ -0,0 +1,102 @@
+package benchmark
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// Supported load models
+const (
+	LoadModelClosed = "closed"
+	LoadModelOpen   = "open"
+)
+
+// openLoopQueueCapacity is the number of scheduled requests the dispatcher can
+// queue ahead of the workers before it has to wait for one to be picked up
+const openLoopQueueCapacity = 10000
+
+// validateLoadModel checks the configured load model name
+func validateLoadModel(model string) error {
+	switch model {
+	case LoadModelClosed, LoadModelOpen:
+		return nil
+	default:
+		return fmt.Errorf("unknown load model: %s (expected %s or %s)", model, LoadModelClosed, LoadModelOpen)
+	}
+}
+
+// OpenLoopDispatcher issues requests on a fixed schedule, independent of how long
+// earlier requests take. Workers receive each request's scheduled start time, so
+// when they fall behind the requests queue up instead of the schedule slipping.
+//
+// With a slot semaphore, every dispatched request holds a slot from dispatch
+// until the worker that executes it releases it. A request scheduled while all
+// slots are taken is shed: skipped and counted, so a stalled cluster cannot pile
+// up outstanding requests.
+type OpenLoopDispatcher struct {
+	jobs     chan time.Time
+	interval time.Duration
+	slots    chan struct{}
+
+	dispatched int64
+	peakDepth  int64
+	shed       int64
+}
+
+// NewOpenLoopDispatcher creates a dispatcher issuing one request per interval.
+// slots may be nil to dispatch without a limit on outstanding requests.
+func NewOpenLoopDispatcher(interval time.Duration, slots chan struct{}) *OpenLoopDispatcher {
+	return &OpenLoopDispatcher{
+		jobs:     make(chan time.Time, openLoopQueueCapacity),
+		interval: interval,
+		slots:    slots,
+	}
+}
+
+// Jobs returns the channel of scheduled start times; it is closed when dispatching stops
+func (d *OpenLoopDispatcher) Jobs() <-chan time.Time {
+	return d.jobs
+}
+
+// Start dispatches scheduled start times from start until end or until ctx is
+// cancelled. If the queue is full it waits, then catches up on the missed slots
+// back to back with their original scheduled times.
+func (d *OpenLoopDispatcher) Start(ctx context.Context, start, end time.Time) {
+	defer close(d.jobs)
+
+	for next := start; next.Before(end); next = next.Add(d.interval) {
+		if !sleepContext(ctx, time.Until(next)) {
+			return
+		}
+		if d.slots != nil {
+			select {
+			case d.slots <- struct{}{}:
+			default:
+				atomic.AddInt64(&d.shed, 1)
+				continue
+			}
+		}
+		select {
+		case d.jobs <- next:
+		case <-ctx.Done():
+			return
+		}
+		atomic.AddInt64(&d.dispatched, 1)
+		if depth := int64(len(d.jobs)); depth > atomic.LoadInt64(&d.peakDepth) {
+			atomic.StoreInt64(&d.peakDepth, depth)
+		}
+	}
+}
+
+// Shed returns the number of scheduled requests skipped because every slot was taken
+func (d *OpenLoopDispatcher) Shed() int64 {
+	return atomic.LoadInt64(&d.shed)
+}
+
+// Snapshot returns the number of dispatched requests, the peak queue depth and
+// the number still queued
+func (d *OpenLoopDispatcher) Snapshot() (int64, int64, int) {
+	return atomic.LoadInt64(&d.dispatched), atomic.LoadInt64(&d.peakDepth), len(d.jobs)
+}
@@ -0,0 +1,175 @@
+package benchmark
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// QueryExecutionMetrics represents metrics for a single query execution
+type QueryExecutionMetrics struct {
+	StartTime           int64         `json:"start_time"`
+	EndTime             int64         `json:"end_time"`
+	Success             bool          `json:"success"`
+	ErrorMessage        string        `json:"error_message,omitempty"`
+	ErrorCategory       string        `json:"error_category,omitempty"`
+	RowCount            int           `json:"row_count"`
+	SDKType             string        `json:"sdk_type"`
+	QueryName           string        `json:"query_name"`
+	Query               string        `json:"query,omitempty"`
+	DurationNanos       int64         `json:"duration_nanos"`
+	DurationMs          float64       `json:"duration_ms"`
+	AbsoluteStartTimeMs int64         `json:"absolute_start_time_ms"`
+	AbsoluteEndTimeMs   int64         `json:"absolute_end_time_ms"`
+	SequenceNumber      int64         `json:"sequence_number"`
+	Timestamp           int64         `json:"timestamp"`
+	ThinkTimeMs         float64       `json:"think_time_ms,omitempty"`
+	SemaphoreWaitMs     float64       `json:"semaphore_wait_ms,omitempty"`
+	RequestBytes        int64         `json:"request_bytes,omitempty"`
+	ResponseBytes       int64         `json:"response_bytes,omitempty"`
+	AfterReconnect      bool          `json:"after_reconnect,omitempty"`
+	ReconnectMs         float64       `json:"reconnect_ms,omitempty"`
+	Phases              *PhaseTimings `json:"phases,omitempty"`
+	EpochMs             int64         `json:"epoch_ms,omitempty"`
+	RelativeStartTimeMs int64         `json:"relative_start_time_ms,omitempty"`
+	RelativeEndTimeMs   int64         `json:"relative_end_time_ms,omitempty"`
+	ResultFormat        string        `json:"result_format,omitempty"`
+	Stage               int           `json:"stage,omitempty"`
+	ThinkTimeMeanMs     int64         `json:"think_time_mean_ms,omitempty"`
+	ThinkTimeDist       string        `json:"think_time_distribution,omitempty"`
+	AllocBytes          uint64        `json:"alloc_bytes,omitempty"`
+	Allocs              uint64        `json:"allocs,omitempty"`
+	QueryVariant        int           `json:"query_variant,omitempty"`
+	DispatchLagMs       float64       `json:"dispatch_lag_ms,omitempty"`
+	RetryCount          int           `json:"retry_count,omitempty"`
+	RampUp              bool          `json:"ramp_up,omitempty"`
+	FirstRowLatencyMs   float64       `json:"first_row_latency_ms,omitempty"`
+	RowDrainMs          float64       `json:"row_drain_ms,omitempty"`
+	WorkerID            *int          `json:"worker_id,omitempty"`
+	Cooldown            bool          `json:"cooldown,omitempty"`
+	ClusterID           string        `json:"cluster_id,omitempty"`
+	ServerElapsedMs     float64       `json:"server_elapsed_ms,omitempty"`
+	ServerExecutionMs   float64       `json:"server_execution_ms,omitempty"`
+	// Parameters and TimeoutMs are what the request was sent with, so a replay can
+	// send it again unchanged. TimeoutMs is only set for a per-query mix timeout.
+	Parameters *QueryParameters `json:"parameters,omitempty"`
+	TimeoutMs  int64            `json:"timeout_ms,omitempty"`
+}
+
+// NewQueryExecutionMetrics creates a new metrics instance
+func NewQueryExecutionMetrics(
+	startTime, endTime time.Time,
+	success bool,
+	errorMessage string,
+	rowCount int,
+	sdkType, queryName string,
+	sequenceNumber int64,
+	absoluteStartTimeMs int64,
+) *QueryExecutionMetrics {
+	durationNanos := endTime.Sub(startTime).Nanoseconds()
+	absoluteEndTimeMs := absoluteStartTimeMs + (durationNanos / 1_000_000)
+
+	return &QueryExecutionMetrics{
+		StartTime:           startTime.UnixNano(),
+		EndTime:             endTime.UnixNano(),
+		Success:             success,
+		ErrorMessage:        errorMessage,
+		RowCount:            rowCount,
+		SDKType:             sdkType,
+		QueryName:           queryName,
+		DurationNanos:       durationNanos,
+		DurationMs:          float64(durationNanos) / 1_000_000.0,
+		AbsoluteStartTimeMs: absoluteStartTimeMs,
+		AbsoluteEndTimeMs:   absoluteEndTimeMs,
+		SequenceNumber:      sequenceNumber,
+		Timestamp:           absoluteStartTimeMs,
+	}
+}
+
+// SetEpoch records the wall-clock timestamps relative to a shared epoch so output
+// from several tester instances can be merged onto one timeline. The absolute
+// timestamps are kept as-is.
+func (m *QueryExecutionMetrics) SetEpoch(epochMs int64) {
+	m.EpochMs = epochMs
+	m.RelativeStartTimeMs = m.AbsoluteStartTimeMs - epochMs
+	m.RelativeEndTimeMs = m.AbsoluteEndTimeMs - epochMs
+}
+
+// SetFirstRow splits the latency at the arrival of the first row: submit to first
+// row, and first row to the last row drained. A zero firstRow (no rows) leaves
+// both unset.
+func (m *QueryExecutionMetrics) SetFirstRow(startTime, firstRow, endTime time.Time) {
+	if firstRow.IsZero() {
+		return
+	}
+	m.FirstRowLatencyMs = float64(firstRow.Sub(startTime).Nanoseconds()) / 1_000_000.0
+	m.RowDrainMs = float64(endTime.Sub(firstRow).Nanoseconds()) / 1_000_000.0
+}
+
+// SetServerTimes records the server-reported elapsed and execution times from
+// the query metadata
+func (m *QueryExecutionMetrics) SetServerTimes(elapsed, execution time.Duration) {
+	m.ServerElapsedMs = float64(elapsed.Nanoseconds()) / 1_000_000.0
+	m.ServerExecutionMs = float64(execution.Nanoseconds()) / 1_000_000.0
+}
+
+// ValidateRowCount turns a successful request into a validation failure when it
+// returned fewer than minRows or more than maxRows rows. A zero bound is not checked.
+func (m *QueryExecutionMetrics) ValidateRowCount(minRows, maxRows int) {
+	if !m.Success {
+		return
+	}
+	switch {
+	case minRows > 0 && m.RowCount < minRows:
+		m.ErrorMessage = fmt.Sprintf("expected at least %d rows, got %d", minRows, m.RowCount)
+	case maxRows > 0 && m.RowCount > maxRows:
+		m.ErrorMessage = fmt.Sprintf("expected at most %d rows, got %d", maxRows, m.RowCount)
+	default:
+		return
+	}
+	m.Success = false
+	m.ErrorCategory = ErrorCategoryValidation
+}
+
+// IsGoodput reports whether the request did useful work: it succeeded without
+// being retried. A row-count validation failure clears Success, so it does not
+// count either; the periodic verification query is separate and not considered.
+func (m *QueryExecutionMetrics) IsGoodput() bool {
+	return m.Success && m.RetryCount == 0
+}
+
+// metricsColumn describes a QueryExecutionMetrics field by its JSON name
+type metricsColumn struct {
+	Name  string
+	Kind  reflect.Kind
+	index int
+}
+
+// metricsColumns lists the serialized metrics fields in declaration order,
+// so tabular writers stay in sync with the JSON output
+var metricsColumns = buildMetricsColumns()
+
+func buildMetricsColumns() []metricsColumn {
+	t := reflect.TypeOf(QueryExecutionMetrics{})
+	columns := make([]metricsColumn, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+		columns = append(columns, metricsColumn{Name: name, Kind: field.Type.Kind(), index: i})
+	}
+	return columns
+}
+
+// metricsValues returns the field values of m in metricsColumns order
+func metricsValues(m *QueryExecutionMetrics) []interface{} {
+	v := reflect.ValueOf(m).Elem()
+	values := make([]interface{}, len(metricsColumns))
+	for i, column := range metricsColumns {
+		values[i] = v.Field(column.index).Interface()
+	}
+	return values
+}
@@ -0,0 +1,462 @@
+package benchmark
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Supported output formats
+const (
+	OutputFormatNDJSON = "ndjson"
+	OutputFormatArray     = "array"
+	OutputFormatCSV       = "csv"
+	OutputFormatSQLite    = "sqlite"
+	OutputFormatHistogram = "histogram"
+)
+
+// Output file names that stream results to stdout instead of a file
+const (
+	stdoutOutputDash = "-"
+	stdoutOutputName = "stdout"
+)
+
+// isStdoutOutput reports whether outputFile names the stdout stream
+func isStdoutOutput(outputFile string) bool {
+	return outputFile == stdoutOutputDash || outputFile == stdoutOutputName
+}
+
+// artifactBase is the path the side files of a run (summary, canaries,
+// verification results) are named after. Streaming to stdout names them
+// after the stream, in the working directory.
+func artifactBase(outputFile string) string {
+	if isStdoutOutput(outputFile) {
+		return stdoutOutputName
+	}
+	return outputFile
+}
+
+// metricsQueueCapacity is the number of results a live sink buffers before
+// dropping; the file output's queue size is configurable
+const metricsQueueCapacity = 1000
+
+// validateOutputFormat checks the configured output format name
+func validateOutputFormat(format string) error {
+	switch format {
+	case OutputFormatNDJSON, OutputFormatArray, OutputFormatCSV, OutputFormatSQLite, OutputFormatHistogram:
+		return nil
+	default:
+		return fmt.Errorf("unknown output format: %s (expected %s, %s, %s, %s or %s)",
+			format, OutputFormatNDJSON, OutputFormatArray, OutputFormatCSV, OutputFormatSQLite, OutputFormatHistogram)
+	}
+}
+
+// MetricsWriter is an output sink the runner streams results into. Workers only
+// see this interface, so new backends plug in through createMetricsWriter.
+type MetricsWriter interface {
+	// Start runs the writer until ctx is cancelled, then drains queued results
+	Start(ctx context.Context)
+	// WriteResult queues a result without blocking the caller
+	WriteResult(metrics *QueryExecutionMetrics)
+	// Wait blocks until Start has returned
+	Wait()
+	GetWrittenCount() int64
+	// GetDroppedCount returns the number of results the writer had to discard
+	GetDroppedCount() int64
+	GetQueueSize() int
+}
+
+var (
+	_ MetricsWriter = (*MetricsJSONWriter)(nil)
+	_ MetricsWriter = (*MetricsCSVWriter)(nil)
+	_ MetricsWriter = (*MetricsSQLiteWriter)(nil)
+	_ MetricsWriter = (*MetricsHistogramWriter)(nil)
+	_ MetricsWriter = (*DegradingMetricsWriter)(nil)
+	_ MetricsWriter = (*SpillingMetricsWriter)(nil)
+	_ MetricsWriter = (*PrometheusWriter)(nil)
+	_ MetricsWriter = (*InfluxWriter)(nil)
+	_ MetricsWriter = (*OTelWriter)(nil)
+	_ MetricsWriter = (*TimeBucketAggregator)(nil)
+	_ MetricsWriter = (*TeeMetricsWriter)(nil)
+	_ MetricsWriter = (*SamplingMetricsWriter)(nil)
+)
+
+// TeeMetricsWriter sends every result to a primary output and to live sinks
+// alongside it. Counts and queue size come from the primary.
+type TeeMetricsWriter struct {
+	primary MetricsWriter
+	live    []MetricsWriter
+}
+
+// NewTeeMetricsWriter creates a writer fanning results out to primary and live
+func NewTeeMetricsWriter(primary MetricsWriter, live ...MetricsWriter) *TeeMetricsWriter {
+	return &TeeMetricsWriter{primary: primary, live: live}
+}
+
+// Start runs every writer until ctx is cancelled
+func (w *TeeMetricsWriter) Start(ctx context.Context) {
+	for _, writer := range w.live {
+		go writer.Start(ctx)
+	}
+	w.primary.Start(ctx)
+}
+
+// WriteResult passes the result to every writer
+func (w *TeeMetricsWriter) WriteResult(metrics *QueryExecutionMetrics) {
+	w.primary.WriteResult(metrics)
+	for _, writer := range w.live {
+		writer.WriteResult(metrics)
+	}
+}
+
+// Wait blocks until every writer has stopped
+func (w *TeeMetricsWriter) Wait() {
+	w.primary.Wait()
+	for _, writer := range w.live {
+		writer.Wait()
+	}
+}
+
+func (w *TeeMetricsWriter) GetWrittenCount() int64 {
+	return w.primary.GetWrittenCount()
+}
+
+func (w *TeeMetricsWriter) GetDroppedCount() int64 {
+	return w.primary.GetDroppedCount()
+}
+
+func (w *TeeMetricsWriter) GetQueueSize() int {
+	return w.primary.GetQueueSize()
+}
+
+// MetricsJSONWriter writes metrics to JSON file
+type MetricsJSONWriter struct {
+	outputFile    string
+	resultChan    chan *QueryExecutionMetrics
+	writtenCount  int64
+	droppedCount  int64
+	done          chan struct{}
+	wg            sync.WaitGroup
+	flushEvery    int
+	flushInterval time.Duration
+	timeBucketMs  int64
+	array         bool
+	compress      bool
+}
+
+// NewMetricsJSONWriter creates a new metrics writer. Output is buffered and flushed
+// after every flushEvery results and every flushInterval, whichever comes first;
+// zero disables the corresponding trigger. A positive timeBucketMs splits the output
+// into one file per time bucket of each result's start time. With array set the
+// file is a single JSON array instead of newline-delimited objects, and with
+// compress set it is gzip-compressed. The queue holds bufferSize results.
+func NewMetricsJSONWriter(outputFile string, bufferSize int, flushEvery int, flushInterval time.Duration, timeBucketMs int64, array, compress bool) *MetricsJSONWriter {
+	return &MetricsJSONWriter{
+		outputFile:    outputFile,
+		timeBucketMs:  timeBucketMs,
+		array:         array,
+		compress:      compress,
+		resultChan:    make(chan *QueryExecutionMetrics, bufferSize),
+		done:          make(chan struct{}),
+		flushEvery:    flushEvery,
+		flushInterval: flushInterval,
+	}
+}
+
+// WriteResult queues a result for writing
+func (w *MetricsJSONWriter) WriteResult(metrics *QueryExecutionMetrics) {
+	select {
+	case w.resultChan <- metrics:
+	case <-w.done:
+		atomic.AddInt64(&w.droppedCount, 1)
+		logWarnf("Attempted to write to closed metrics writer")
+	default:
+		atomic.AddInt64(&w.droppedCount, 1)
+		logWarnf("Metrics writer queue full, dropping result")
+	}
+}
+
+// Start begins the writer goroutine
+func (w *MetricsJSONWriter) Start(ctx context.Context) {
+	w.wg.Add(1)
+	defer w.wg.Done()
+	
+	if !isStdoutOutput(w.outputFile) {
+		if err := os.MkdirAll(filepath.Dir(w.outputFile), 0755); err != nil {
+			logErrorf("Failed to create output directory: %v", err)
+			return
+		}
+	}
+	
+	// Log lines go to stderr, so they never interleave with a stdout stream
+	log.Printf("MetricsJSONWriter starting for file: %s", w.outputFile)
+	
+	var output *jsonOutput
+	currentBucket := int64(-1)
+	if w.timeBucketMs <= 0 {
+		var err error
+		if output, err = openJSONOutput(w.outputFile, false, w.array, w.compress); err != nil {
+			logErrorf("Failed to create output file: %v", err)
+			return
+		}
+	}
+	// Closing the output also terminates an array and ends the gzip stream, so every
+	// exit path, cancellation included, leaves a complete file
+	defer func() {
+		if output != nil {
+			output.Close()
+		}
+	}()
+	
+	pending := 0
+	flush := func() {
+		if pending == 0 || output == nil {
+			return
+		}
+		if err := output.Flush(); err != nil {
+			logErrorf("Failed to flush output file: %v", err)
+		}
+		pending = 0
+	}
+	
+	// Bucket files this run has written to. A bucket file is truncated the first
+	// time, so a rerun within the same bucket window does not append to the
+	// previous run's file, and only appended to after that.
+	bucketsWritten := make(map[int64]bool)
+	
+	// encode writes a result to its output file, rotating to a new time-bucket file
+	// when the result starts a later bucket
+	encode := func(result *QueryExecutionMetrics) error {
+		if w.timeBucketMs <= 0 {
+			return output.Write(result)
+		}
+		
+		bucket := result.AbsoluteStartTimeMs - result.AbsoluteStartTimeMs%w.timeBucketMs
+		if bucket < currentBucket {
+			// A request that started before the last rotation goes back to its own bucket's file
+			path := timeBucketPath(w.outputFile, bucket)
+			if bucketsWritten[bucket] {
+				return appendToJSONFile(path, result, w.compress)
+			}
+			bucketsWritten[bucket] = true
+			fresh, err := openJSONOutput(path, false, false, w.compress)
+			if err != nil {
+				return err
+			}
+			defer fresh.Close()
+			return fresh.Write(result)
+		}
+		if bucket > currentBucket {
+			if output != nil {
+				output.Close()
+				pending = 0
+			}
+			path := timeBucketPath(w.outputFile, bucket)
+			log.Printf("MetricsJSONWriter rotating to file: %s", path)
+			// Buckets only rotate forward, so this run has not written to this one yet
+			bucketsWritten[bucket] = true
+			opened, err := openJSONOutput(path, false, false, w.compress)
+			if err != nil {
+				output = nil
+				return err
+			}
+			output, currentBucket = opened, bucket
+		}
+		return output.Write(result)
+	}
+	
+	var flushTick <-chan time.Time
+	if w.flushInterval > 0 {
+		ticker := time.NewTicker(w.flushInterval)
+		defer ticker.Stop()
+		flushTick = ticker.C
+	}
+	
+	for {
+		select {
+		case <-ctx.Done():
+			log.Printf("MetricsJSONWriter shutting down, draining remaining results...")
+			close(w.done) 
+			
+			drained := 0
+			for {
+				select {
+				case result := <-w.resultChan:
+					if err := encode(result); err != nil {
+						logErrorf("Failed to encode result during shutdown: %v", err)
+					} else {
+						atomic.AddInt64(&w.writtenCount, 1)
+						drained++
+					}
+				default:
+					log.Printf("MetricsJSONWriter completed. Total results written: %d (drained %d during shutdown)",
+						atomic.LoadInt64(&w.writtenCount), drained)
+					return
+				}
+			}
+			
+		case result := <-w.resultChan:
+			if err := encode(result); err != nil {
+				logErrorf("Failed to encode result: %v", err)
+			} else {
+				count := atomic.AddInt64(&w.writtenCount, 1)
+				if count <= 5 || count%500 == 0 {
+					log.Printf("Wrote result #%d", count)
+				}
+				pending++
+				if w.flushEvery > 0 && pending >= w.flushEvery {
+					flush()
+				}
+			}
+			
+		case <-flushTick:
+			flush()
+		}
+	}
+}
+
+// jsonOutput is an open, buffered NDJSON or JSON array output file, optionally
+// gzip-compressed
+type jsonOutput struct {
+	file     *os.File
+	gzip     *gzip.Writer
+	buffered *bufio.Writer
+	encoder  *json.Encoder
+	array    bool
+	written  int
+}
+
+// openJSONOutput creates path, or appends to it when appendExisting is set. The
+// stdout names write to os.Stdout instead. An array output starts with the
+// opening bracket; Close writes the closing one. With compress set, appending adds
+// a new gzip member, which gzip readers concatenate transparently.
+func openJSONOutput(path string, appendExisting, array, compress bool) (*jsonOutput, error) {
+	if isStdoutOutput(path) {
+		return newJSONOutput(os.Stdout, array, false), nil
+	}
+	
+	flags := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	if appendExisting {
+		flags = os.O_CREATE | os.O_WRONLY | os.O_APPEND
+	}
+	file, err := os.OpenFile(path, flags, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return newJSONOutput(file, array, compress), nil
+}
+
+// newJSONOutput wraps an open file in a buffered encoder, compressing through
+// gzip when compress is set
+func newJSONOutput(file *os.File, array, compress bool) *jsonOutput {
+	output := &jsonOutput{file: file, array: array}
+	if compress {
+		output.gzip = gzip.NewWriter(file)
+		output.buffered = bufio.NewWriter(output.gzip)
+	} else {
+		output.buffered = bufio.NewWriter(file)
+	}
+	output.encoder = json.NewEncoder(output.buffered)
+	if array {
+		output.buffered.WriteString("[")
+	}
+	return output
+}
+
+// Write encodes one result, comma-separating array elements
+func (o *jsonOutput) Write(result *QueryExecutionMetrics) error {
+	if !o.array {
+		return o.encoder.Encode(result)
+	}
+	
+	data, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+	separator := ",\n"
+	if o.written == 0 {
+		separator = "\n"
+	}
+	o.buffered.WriteString(separator)
+	if _, err := o.buffered.Write(data); err != nil {
+		return err
+	}
+	o.written++
+	return nil
+}
+
+// Flush pushes buffered results to the file. A compressed output is flushed to a
+// gzip block boundary, so everything written so far can be decompressed even if
+// the process is killed before Close.
+func (o *jsonOutput) Flush() error {
+	if err := o.buffered.Flush(); err != nil {
+		return err
+	}
+	if o.gzip != nil {
+		return o.gzip.Flush()
+	}
+	return nil
+}
+
+// Close terminates an array, flushes buffered output, writes the gzip trailer and
+// closes the file. Stdout is left open.
+func (o *jsonOutput) Close() {
+	if o.array {
+		o.buffered.WriteString("\n]\n")
+	}
+	if err := o.buffered.Flush(); err != nil {
+		logErrorf("Failed to flush output file: %v", err)
+	}
+	if o.gzip != nil {
+		if err := o.gzip.Close(); err != nil {
+			logErrorf("Failed to finish compressed output file: %v", err)
+		}
+	}
+	if o.file != os.Stdout {
+		o.file.Close()
+	}
+}
+
+// appendToJSONFile appends a single result to an already rotated-away file
+func appendToJSONFile(path string, result *QueryExecutionMetrics, compress bool) error {
+	output, err := openJSONOutput(path, true, false, compress)
+	if err != nil {
+		return err
+	}
+	defer output.Close()
+	return output.Write(result)
+}
+
+// timeBucketPath names the file for the time bucket starting at bucketStartMs by
+// inserting the UTC start time before the extension, e.g. results.20240101T130000Z.json
+func timeBucketPath(outputFile string, bucketStartMs int64) string {
+	ext := filepath.Ext(outputFile)
+	stamp := time.UnixMilli(bucketStartMs).UTC().Format("20060102T150405Z")
+	return strings.TrimSuffix(outputFile, ext) + "." + stamp + ext
+}
+
+func (w *MetricsJSONWriter) Wait() {
+	w.wg.Wait()
+}
+
+func (w *MetricsJSONWriter) GetWrittenCount() int64 {
+	return atomic.LoadInt64(&w.writtenCount)
+}
+
+// GetDroppedCount returns the number of results lost to a full queue or a closed writer
+func (w *MetricsJSONWriter) GetDroppedCount() int64 {
+	return atomic.LoadInt64(&w.droppedCount)
+}
+
+func (w *MetricsJSONWriter) GetQueueSize() int {
+	return len(w.resultChan)
+} 
\ No newline at end of file
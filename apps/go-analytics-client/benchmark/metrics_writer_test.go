@@ -0,0 +1,124 @@
+package benchmark
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// largeSequenceCounters are counter values just below the int32 and int64 limits,
+// so the next sequence numbers cross into the range a 32-bit field would corrupt
+var largeSequenceCounters = []int64{math.MaxInt32 - 1, math.MaxInt64 - 2}
+
+// recordLargeSequences issues two sequence numbers from a runner counter starting
+// at start, the way workers do, and returns their results
+func recordLargeSequences(start int64) []*QueryExecutionMetrics {
+	runner := &SimpleAnalyticsRunner{sequenceCounter: start}
+	now := time.Now()
+
+	var results []*QueryExecutionMetrics
+	for i := 0; i < 2; i++ {
+		seq := atomic.AddInt64(&runner.sequenceCounter, 1)
+		results = append(results, NewQueryExecutionMetrics(now, now.Add(time.Millisecond), true, "", 1,
+			"operational", "default", seq, now.UnixMilli()))
+	}
+	return results
+}
+
+// runWriter writes results through w and waits for it to finish
+func runWriter(w MetricsWriter, results []*QueryExecutionMetrics) {
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		w.Start(ctx)
+		close(stopped)
+	}()
+	for _, result := range results {
+		w.WriteResult(result)
+	}
+	cancel()
+	<-stopped
+}
+
+func TestJSONWriterKeepsLargeSequenceNumbers(t *testing.T) {
+	for _, start := range largeSequenceCounters {
+		results := recordLargeSequences(start)
+		path := filepath.Join(t.TempDir(), "metrics.json")
+		runWriter(NewMetricsJSONWriter(path, 10, 1, time.Second, 0, false, false), results)
+
+		file, err := os.Open(path)
+		if err != nil {
+			t.Fatalf("open output: %v", err)
+		}
+		defer file.Close()
+
+		var got []int64
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			var record QueryExecutionMetrics
+			if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+				t.Fatalf("decode %q: %v", scanner.Text(), err)
+			}
+			got = append(got, record.SequenceNumber)
+		}
+		checkSequenceNumbers(t, start, results, got)
+	}
+}
+
+func TestCSVWriterKeepsLargeSequenceNumbers(t *testing.T) {
+	for _, start := range largeSequenceCounters {
+		results := recordLargeSequences(start)
+		path := filepath.Join(t.TempDir(), "metrics.csv")
+		runWriter(NewMetricsCSVWriter(path, 10), results)
+
+		file, err := os.Open(path)
+		if err != nil {
+			t.Fatalf("open output: %v", err)
+		}
+		defer file.Close()
+
+		records, err := csv.NewReader(file).ReadAll()
+		if err != nil {
+			t.Fatalf("read output: %v", err)
+		}
+		column := -1
+		for i, name := range records[0] {
+			if name == "sequence_number" {
+				column = i
+			}
+		}
+		if column < 0 {
+			t.Fatalf("no sequence_number column in header %v", records[0])
+		}
+
+		var got []int64
+		for _, record := range records[1:] {
+			seq, err := strconv.ParseInt(record[column], 10, 64)
+			if err != nil {
+				t.Fatalf("parse sequence_number %q: %v", record[column], err)
+			}
+			got = append(got, seq)
+		}
+		checkSequenceNumbers(t, start, results, got)
+	}
+}
+
+func checkSequenceNumbers(t *testing.T, start int64, results []*QueryExecutionMetrics, got []int64) {
+	t.Helper()
+	if len(got) != len(results) {
+		t.Fatalf("counter at %d: wrote %d results, want %d", start, len(got), len(results))
+	}
+	for i, result := range results {
+		if got[i] != result.SequenceNumber {
+			t.Errorf("counter at %d: result %d has sequence number %d, want %d", start, i, got[i], result.SequenceNumber)
+		}
+	}
+}
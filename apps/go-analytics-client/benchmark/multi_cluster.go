@@ -0,0 +1,192 @@
+package benchmark
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// ClusterTarget is one cluster of a multi-cluster run and its share of the load
+type ClusterTarget struct {
+	ID               string `yaml:"id"`
+	ConnectionString string `yaml:"connection_string"`
+	Weight           int    `yaml:"weight"`
+}
+
+// validateConnections checks the connection settings: either the single
+// connection string or a clusters list, in which every cluster has a unique ID, a
+// positive weight and a connection string valid for the other settings
+func validateConnections(config Configuration) error {
+	if len(config.Clusters) == 0 {
+		if config.ConnectionString == "" {
+			return fmt.Errorf("missing required settings: CLUSTER_CONNECTION_STRING (connection_string), or a clusters list")
+		}
+		if err := validateTLSOptions(config); err != nil {
+			return err
+		}
+		return validateAnalyticsEndpoint(config)
+	}
+
+	seen := make(map[string]bool, len(config.Clusters))
+	for i, cluster := range config.Clusters {
+		if cluster.ID == "" || cluster.ConnectionString == "" {
+			return fmt.Errorf("clusters[%d]: id and connection_string are required", i)
+		}
+		if seen[cluster.ID] {
+			return fmt.Errorf("clusters[%d]: duplicate id %q", i, cluster.ID)
+		}
+		seen[cluster.ID] = true
+		if cluster.Weight <= 0 {
+			return fmt.Errorf("cluster %q: weight must be positive, got %d", cluster.ID, cluster.Weight)
+		}
+
+		clusterConfig := config
+		clusterConfig.ConnectionString = cluster.ConnectionString
+		if err := validateTLSOptions(clusterConfig); err != nil {
+			return fmt.Errorf("cluster %q: %w", cluster.ID, err)
+		}
+		if err := validateAnalyticsEndpoint(clusterConfig); err != nil {
+			return fmt.Errorf("cluster %q: %w", cluster.ID, err)
+		}
+	}
+	return nil
+}
+
+// MultiClusterHandler spreads requests over one handler per cluster in
+// proportion to the cluster weights, tagging each result with the cluster that
+// served it. It is safe for concurrent use.
+type MultiClusterHandler struct {
+	handlers []AnalyticsSDKHandler
+	ids      []string
+	schedule []int
+	next     uint64
+}
+
+// NewMultiClusterHandler opens a handler for every cluster with create, which
+// receives the cluster's connection string
+func NewMultiClusterHandler(clusters []ClusterTarget, create func(connectionString string) (AnalyticsSDKHandler, error)) (*MultiClusterHandler, error) {
+	h := &MultiClusterHandler{}
+	weights := make([]int, 0, len(clusters))
+	for _, cluster := range clusters {
+		handler, err := create(cluster.ConnectionString)
+		if err != nil {
+			h.Close()
+			return nil, fmt.Errorf("cluster %q: %w", cluster.ID, err)
+		}
+		h.handlers = append(h.handlers, handler)
+		h.ids = append(h.ids, cluster.ID)
+		weights = append(weights, cluster.Weight)
+	}
+	h.schedule = weightedSchedule(weights)
+	return h, nil
+}
+
+// weightedSchedule returns one cycle of cluster indexes in which each cluster
+// appears in proportion to its weight, spread out rather than in runs (smooth
+// weighted round-robin)
+func weightedSchedule(weights []int) []int {
+	divisor := 0
+	for _, weight := range weights {
+		divisor = gcd(divisor, weight)
+	}
+	total := 0
+	for _, weight := range weights {
+		total += weight / divisor
+	}
+
+	schedule := make([]int, 0, total)
+	current := make([]int, len(weights))
+	for len(schedule) < total {
+		best := 0
+		for i, weight := range weights {
+			current[i] += weight / divisor
+			if current[i] > current[best] {
+				best = i
+			}
+		}
+		current[best] -= total
+		schedule = append(schedule, best)
+	}
+	return schedule
+}
+
+func gcd(a, b int) int {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
+// pick returns the index of the cluster for the next request
+func (h *MultiClusterHandler) pick() int {
+	return h.schedule[(atomic.AddUint64(&h.next, 1)-1)%uint64(len(h.schedule))]
+}
+
+// ExecuteQuery executes the query on the next cluster in the weighted schedule
+func (h *MultiClusterHandler) ExecuteQuery(ctx context.Context, query, queryName string, sequenceNumber int64, timeout time.Duration, params QueryParameters) *QueryExecutionMetrics {
+	i := h.pick()
+	result := h.handlers[i].ExecuteQuery(ctx, query, queryName, sequenceNumber, timeout, params)
+	result.ClusterID = h.ids[i]
+	return result
+}
+
+// FetchRows executes a query on the next cluster in the weighted schedule
+func (h *MultiClusterHandler) FetchRows(query string) ([]json.RawMessage, error) {
+	return h.handlers[h.pick()].FetchRows(query)
+}
+
+// TraceQuery traces a query on the next cluster in the weighted schedule
+func (h *MultiClusterHandler) TraceQuery(query string, params QueryParameters, canary int64) *CanaryTrace {
+	return h.handlers[h.pick()].TraceQuery(query, params, canary)
+}
+
+// GetSDKType returns the SDK type shared by every cluster's handler
+func (h *MultiClusterHandler) GetSDKType() string {
+	return h.handlers[0].GetSDKType()
+}
+
+// ConnectionTimings returns how long opening every cluster's connections took
+func (h *MultiClusterHandler) ConnectionTimings() []ConnectionTiming {
+	var timings []ConnectionTiming
+	for i, handler := range h.handlers {
+		timer, ok := handler.(connectionTimer)
+		if !ok {
+			continue
+		}
+		for _, timing := range timer.ConnectionTimings() {
+			timing.Cluster = h.ids[i]
+			timings = append(timings, timing)
+		}
+	}
+	return timings
+}
+
+// HealthReconnects returns the health monitor reconnects of every cluster
+func (h *MultiClusterHandler) HealthReconnects() []HealthReconnectEvent {
+	var events []HealthReconnectEvent
+	for i, handler := range h.handlers {
+		reporter, ok := handler.(healthReporter)
+		if !ok {
+			continue
+		}
+		for _, event := range reporter.HealthReconnects() {
+			event.Cluster = h.ids[i]
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+// Close closes every cluster's handler
+func (h *MultiClusterHandler) Close() error {
+	var errs []error
+	for _, handler := range h.handlers {
+		if err := handler.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
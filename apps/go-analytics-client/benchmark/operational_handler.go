@@ -0,0 +1,421 @@
+package benchmark
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/couchbase/gocb/v2"
+)
+
+// OperationalSDKHandler handles operational SDK operations. Requests are spread
+// round-robin over one or more cluster connections.
+type OperationalSDKHandler struct {
+	conns           []atomic.Pointer[operationalConnection]
+	timings         []ConnectionTiming
+	health          *HealthMonitor
+	keepAlive       *KeepAlive
+	next            uint64
+	sizeSampleEvery int
+	capturePhases   bool
+	countRowsOnly   bool
+	scanConsistency gocb.AnalyticsScanConsistency
+	retry           RetryPolicy
+}
+
+// NewOperationalSDKHandler creates a new operational SDK handler with
+// config.NumConnections cluster connections
+func NewOperationalSDKHandler(config Configuration) (*OperationalSDKHandler, error) {
+	retry, err := NewRetryPolicy(config)
+	if err != nil {
+		return nil, err
+	}
+	
+	handler := &OperationalSDKHandler{
+		sizeSampleEvery: config.SizeSampleEvery,
+		capturePhases:   config.CapturePhases,
+		countRowsOnly:   config.CountRowsOnly,
+		scanConsistency: operationalScanConsistency(config.ScanConsistency),
+		retry:           retry,
+	}
+	handler.conns = make([]atomic.Pointer[operationalConnection], config.NumConnections)
+	for i := range handler.conns {
+		cluster, timing, err := connectOperationalCluster(config)
+		if err != nil {
+			handler.Close()
+			return nil, err
+		}
+		timing.Connection = i
+		handler.timings = append(handler.timings, timing)
+		handler.conns[i].Store(&operationalConnection{cluster: cluster, querier: operationalQuerierFor(cluster, config)})
+	}
+	
+	if config.HealthcheckIntervalMs > 0 {
+		handler.health = NewHealthMonitor(len(handler.conns),
+			time.Duration(config.HealthcheckIntervalMs)*time.Millisecond,
+			time.Duration(config.ConnectionTimeoutS)*time.Second, config.HealthcheckFailures,
+			func(ctx context.Context, i int) error {
+				return runOperationalHealthcheck(ctx, handler.conns[i].Load().cluster)
+			},
+			func(i int) error {
+				cluster, _, err := connectOperationalCluster(config)
+				if err != nil {
+					return err
+				}
+				old := handler.conns[i].Swap(&operationalConnection{cluster: cluster, querier: operationalQuerierFor(cluster, config)})
+				old.cluster.Close(nil)
+				return nil
+			})
+		handler.health.Start()
+	}
+	if config.KeepAliveIntervalMs > 0 {
+		handler.keepAlive = NewKeepAlive(len(handler.conns),
+			time.Duration(config.KeepAliveIntervalMs)*time.Millisecond,
+			time.Duration(config.ConnectionTimeoutS)*time.Second,
+			func(ctx context.Context, i int) error {
+				return runOperationalHealthcheck(ctx, handler.conns[i].Load().cluster)
+			})
+		handler.keepAlive.Start()
+	}
+	
+	return handler, nil
+}
+
+// operationalScanConsistency maps a scan consistency name to gocb's; zero leaves
+// the SDK default
+func operationalScanConsistency(consistency string) gocb.AnalyticsScanConsistency {
+	switch consistency {
+	case ScanConsistencyNotBounded:
+		return gocb.AnalyticsScanConsistencyNotBounded
+	case ScanConsistencyRequestPlus:
+		return gocb.AnalyticsScanConsistencyRequestPlus
+	default:
+		return 0
+	}
+}
+
+// operationalConnection is one cluster connection and the querier requests run on
+type operationalConnection struct {
+	cluster *gocb.Cluster
+	querier operationalQuerier
+}
+
+// connectOperationalCluster opens one cluster connection and waits until it is
+// ready, timing both
+func connectOperationalCluster(config Configuration) (*gocb.Cluster, ConnectionTiming, error) {
+	var timing ConnectionTiming
+	security, err := operationalSecurityConfig(config)
+	if err != nil {
+		return nil, timing, err
+	}
+	authenticator, err := operationalAuthenticator(config)
+	if err != nil {
+		return nil, timing, err
+	}
+	
+	// Create cluster options
+	opts := gocb.ClusterOptions{
+		Authenticator: authenticator,
+		TimeoutsConfig: gocb.TimeoutsConfig{
+			AnalyticsTimeout:  time.Duration(config.AnalyticsTimeoutS) * time.Second,
+			ConnectTimeout:    time.Duration(config.ConnectionTimeoutS) * time.Second,
+		},
+		SecurityConfig: security,
+	}
+	
+	// Connect to cluster
+	start := time.Now()
+	cluster, err := gocb.Connect(operationalConnectionString(config), opts)
+	if err != nil {
+		return nil, timing, fmt.Errorf("failed to connect to cluster: %w", err)
+	}
+	
+	// Wait until ready
+	err = cluster.WaitUntilReady(time.Duration(config.ConnectionTimeoutS)*time.Second, nil)
+	timing.ConnectMs = float64(time.Since(start).Nanoseconds()) / 1_000_000.0
+	timing.ConnectionTimeMs = timing.ConnectMs
+	if err != nil {
+		if err := applyHealthcheckPolicy(config.HealthcheckPolicy, fmt.Errorf("cluster not ready: %w", err)); err != nil {
+			cluster.Close(nil)
+			return nil, timing, err
+		}
+	} else {
+		log.Printf("✅ Operational SDK connected successfully in %.2fms", timing.ConnectionTimeMs)
+	}
+	return cluster, timing, nil
+}
+
+// runOperationalHealthcheck runs the probe query on the analytics service and checks its result
+func runOperationalHealthcheck(ctx context.Context, cluster *gocb.Cluster) error {
+	result, err := cluster.AnalyticsQuery("SELECT 1 as test", &gocb.AnalyticsOptions{Context: ctx})
+	if err != nil {
+		return fmt.Errorf("failed to test analytics connection: %w", err)
+	}
+	
+	var probeRows []json.RawMessage
+	for result.Next() {
+		var raw json.RawMessage
+		if err := result.Row(&raw); err == nil {
+			probeRows = append(probeRows, raw)
+		}
+	}
+	
+	if err := result.Err(); err != nil {
+		return fmt.Errorf("failed to test analytics connection: %w", err)
+	}
+	if err := checkHealthcheckRows(probeRows); err != nil {
+		return fmt.Errorf("analytics connection test returned an unexpected result: %w", err)
+	}
+	return nil
+}
+
+// querier returns the cluster, or the query context's scope, of the connection
+// for the next request, round-robin
+func (h *OperationalSDKHandler) querier() operationalQuerier {
+	return h.conns[(atomic.AddUint64(&h.next, 1)-1)%uint64(len(h.conns))].Load().querier
+}
+
+// operationalConnectionString appends the configured HTTP connection pool options,
+// which gocb only reads from the connection string
+func operationalConnectionString(config Configuration) string {
+	var options []string
+	if config.HTTPIdleConnTimeoutMs >= 0 {
+		options = append(options, fmt.Sprintf("http_idle_conn_timeout=%d", config.HTTPIdleConnTimeoutMs))
+	}
+	if config.HTTPMaxIdleConnsPerHost > 0 {
+		options = append(options, fmt.Sprintf("http_max_idle_conns_per_host=%d", config.HTTPMaxIdleConnsPerHost))
+	}
+	if len(options) == 0 {
+		return config.ConnectionString
+	}
+	
+	separator := "?"
+	if strings.Contains(config.ConnectionString, "?") {
+		separator = "&"
+	}
+	return config.ConnectionString + separator + strings.Join(options, "&")
+}
+
+// ExecuteQuery executes a query using the operational SDK
+func (h *OperationalSDKHandler) ExecuteQuery(ctx context.Context, query, queryName string, sequenceNumber int64, timeout time.Duration, params QueryParameters) *QueryExecutionMetrics {
+	absoluteStartTimeMs := time.Now().UnixMilli()
+	startTime := time.Now()
+	
+	if sequenceNumber <= 10 || sequenceNumber%1000 == 0 {
+		logDebugf("Executing operational analytics query #%d", sequenceNumber)
+	}
+	
+	// A zero Timeout falls back to the cluster-wide AnalyticsTimeout. Transient
+	// failures are retried before any rows are read.
+	querier := h.querier()
+	var result *gocb.AnalyticsResult
+	retries, err := h.retry.Do(ctx, func() error {
+		var err error
+		result, err = querier.AnalyticsQuery(query, &gocb.AnalyticsOptions{
+			Timeout:              timeout,
+			Context:              ctx,
+			PositionalParameters: params.Positional,
+			NamedParameters:      params.Named,
+			ScanConsistency:      h.scanConsistency,
+		})
+		return err
+	})
+	
+	if err != nil {
+		endTime := time.Now() // Capture end time for errors
+		logWarnf("Operational analytics query #%d failed after %v (%d retries): %v",
+			sequenceNumber, endTime.Sub(startTime), retries, err)
+		
+		metrics := NewQueryExecutionMetrics(
+			startTime, endTime, false, err.Error(), 0,
+			"operational", queryName, sequenceNumber, absoluteStartTimeMs,
+		)
+		metrics.RetryCount = retries
+		metrics.ErrorCategory = classifyError(err)
+		return metrics
+	}
+	
+	// Count rows, summing raw row sizes for sampled requests. Other rows are
+	// decoded like a real client would, unless only counting them.
+	sampleSize := h.sizeSampleEvery > 0 && sequenceNumber%int64(h.sizeSampleEvery) == 0
+	rowCount := 0
+	var responseBytes int64
+	var firstRow time.Time
+	for result.Next() {
+		if rowCount == 0 {
+			firstRow = time.Now()
+		}
+		rowCount++
+		if sampleSize {
+			var raw json.RawMessage
+			if err := result.Row(&raw); err == nil {
+				responseBytes += int64(len(raw))
+			}
+			continue
+		}
+		if h.countRowsOnly {
+			continue
+		}
+		var row interface{}
+		result.Row(&row)
+	}
+	
+	// ✅ FIXED: Capture end time AFTER row processing
+	endTime := time.Now()
+	
+	if err := result.Err(); err != nil {
+		logWarnf("Operational analytics query #%d row iteration failed: %v", sequenceNumber, err)
+		metrics := NewQueryExecutionMetrics(
+			startTime, endTime, false, err.Error(), rowCount,
+			"operational", queryName, sequenceNumber, absoluteStartTimeMs,
+		)
+		metrics.RetryCount = retries
+		metrics.ErrorCategory = classifyError(err)
+		return metrics
+	}
+	
+	result.Close()
+	
+	metrics := NewQueryExecutionMetrics(
+		startTime, endTime, true, "", rowCount,
+		"operational", queryName, sequenceNumber, absoluteStartTimeMs,
+	)
+	metrics.RetryCount = retries
+	metrics.SetFirstRow(startTime, firstRow, endTime)
+	if sampleSize {
+		metrics.RequestBytes = int64(len(query))
+		metrics.ResponseBytes = responseBytes
+	}
+	// Metadata is only available once every row has been read; without it the
+	// server times are left unset
+	if meta, err := result.MetaData(); err == nil {
+		metrics.SetServerTimes(meta.Metrics.ElapsedTime, meta.Metrics.ExecutionTime)
+		if h.capturePhases {
+			metrics.Phases = NewPhaseTimings(endTime.Sub(startTime), meta.Metrics.ElapsedTime, meta.Metrics.ExecutionTime)
+		}
+	}
+	return metrics
+}
+
+// FetchRows executes a query and returns its raw rows
+func (h *OperationalSDKHandler) FetchRows(query string) ([]json.RawMessage, error) {
+	result, err := h.querier().AnalyticsQuery(query, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer result.Close()
+	
+	var rows []json.RawMessage
+	for result.Next() {
+		var raw json.RawMessage
+		if err := result.Row(&raw); err != nil {
+			return nil, err
+		}
+		rows = append(rows, raw)
+	}
+	
+	if err := result.Err(); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// TraceQuery executes a query capturing all the metadata gocb exposes. The
+// client context ID tags the request so it can be found in server logs.
+func (h *OperationalSDKHandler) TraceQuery(query string, params QueryParameters, canary int64) *CanaryTrace {
+	trace := &CanaryTrace{
+		TimestampMs:     time.Now().UnixMilli(),
+		SDKType:         "operational",
+		Query:           query,
+		ClientContextID: fmt.Sprintf("canary-%d-%d", time.Now().UnixNano(), canary),
+	}
+	
+	startTime := time.Now()
+	result, err := h.querier().AnalyticsQuery(query, &gocb.AnalyticsOptions{
+		ClientContextID:      trace.ClientContextID,
+		PositionalParameters: params.Positional,
+		NamedParameters:      params.Named,
+		ScanConsistency:      h.scanConsistency,
+	})
+	if err != nil {
+		trace.DurationMs = float64(time.Since(startTime).Nanoseconds()) / 1_000_000.0
+		trace.ErrorMessage = err.Error()
+		// The serving node is only exposed on errors
+		var analyticsErr *gocb.AnalyticsError
+		if errors.As(err, &analyticsErr) {
+			trace.Node = analyticsErr.Endpoint
+		}
+		return trace
+	}
+	
+	for result.Next() {
+		if trace.RowCount == 0 {
+			trace.TimeToFirstRowMs = float64(time.Since(startTime).Nanoseconds()) / 1_000_000.0
+		}
+		trace.RowCount++
+		var raw json.RawMessage
+		if err := result.Row(&raw); err == nil {
+			trace.ResponseBytes += int64(len(raw))
+		}
+	}
+	endTime := time.Now()
+	trace.DurationMs = float64(endTime.Sub(startTime).Nanoseconds()) / 1_000_000.0
+	
+	if err := result.Err(); err != nil {
+		trace.ErrorMessage = err.Error()
+		return trace
+	}
+	result.Close()
+	trace.Success = true
+	
+	if meta, err := result.MetaData(); err == nil {
+		trace.RequestID = meta.RequestID
+		trace.ServerElapsedMs = float64(meta.Metrics.ElapsedTime.Nanoseconds()) / 1_000_000.0
+		trace.ServerExecutionMs = float64(meta.Metrics.ExecutionTime.Nanoseconds()) / 1_000_000.0
+		trace.ResultCount = meta.Metrics.ResultCount
+		trace.ResultSize = meta.Metrics.ResultSize
+		trace.ProcessedObjects = meta.Metrics.ProcessedObjects
+		for _, warning := range meta.Warnings {
+			trace.Warnings = append(trace.Warnings, fmt.Sprintf("%d: %s", warning.Code, warning.Message))
+		}
+		trace.Phases = NewPhaseTimings(endTime.Sub(startTime), meta.Metrics.ElapsedTime, meta.Metrics.ExecutionTime)
+	}
+	return trace
+}
+
+// GetSDKType returns the SDK type
+func (h *OperationalSDKHandler) GetSDKType() string {
+	return "operational"
+}
+
+// ConnectionTimings returns how long opening each initial connection took
+func (h *OperationalSDKHandler) ConnectionTimings() []ConnectionTiming {
+	return h.timings
+}
+
+// HealthReconnects returns the connections replaced after failing health checks
+func (h *OperationalSDKHandler) HealthReconnects() []HealthReconnectEvent {
+	return h.health.Events()
+}
+
+// Close stops the health monitor and keepalive and closes every cluster connection
+func (h *OperationalSDKHandler) Close() error {
+	h.health.Close()
+	h.keepAlive.Close()
+	var errs []error
+	for i := range h.conns {
+		conn := h.conns[i].Load()
+		if conn == nil {
+			continue
+		}
+		if err := conn.cluster.Close(nil); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
\ No newline at end of file
@@ -0,0 +1,278 @@
+package benchmark
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	otelServiceName   = "analytics-performance-tester"
+	otelScopeName     = "go-analytics-client"
+	otelSpanName      = "analytics.query"
+	otelHistogramName = "analytics.query.duration"
+	otelExportTimeout = 10 * time.Second
+
+	otelTracesPath  = "/v1/traces"
+	otelMetricsPath = "/v1/metrics"
+)
+
+// otelHistogramBoundsMs are the explicit bucket bounds of the latency histogram
+var otelHistogramBoundsMs = []float64{1, 2, 5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000, 10000, 30000, 60000}
+
+// OTelWriter exports every result to an OpenTelemetry collector over OTLP/HTTP
+// using the OpenTelemetry SDK: each query as a client span, and the query latency
+// as a cumulative histogram metric per SDK, query name and outcome. Spans go
+// through a batch span processor, flushed when a batch fills or the flush
+// interval passes, and the histogram through a periodic reader, so exports run
+// off the workers' path and a slow collector only fills the processor's queue.
+// A failed export drops its batch and costs a log line per outage.
+type OTelWriter struct {
+	tracerProvider *sdktrace.TracerProvider
+	meterProvider  *sdkmetric.MeterProvider
+	tracer         trace.Tracer
+	latency        metric.Float64Histogram
+	status         *otelExportStatus
+
+	submittedCount int64
+	closedCount    int64
+	done           chan struct{}
+	stopped        chan struct{}
+}
+
+// NewOTelWriter creates a writer exporting to the OTLP/HTTP collector at endpoint,
+// e.g. http://localhost:4318
+func NewOTelWriter(endpoint, runTimestamp string, batchSize int, flushInterval time.Duration) (*OTelWriter, error) {
+	base := strings.TrimSuffix(endpoint, "/")
+	if u, err := url.Parse(base); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("BENCHMARK_OTEL_ENDPOINT must be an http or https URL, got %q", endpoint)
+	}
+
+	ctx := context.Background()
+	traceExporter, err := otlptracehttp.New(ctx,
+		otlptracehttp.WithEndpointURL(base+otelTracesPath),
+		otlptracehttp.WithTimeout(otelExportTimeout),
+		otlptracehttp.WithRetry(otlptracehttp.RetryConfig{Enabled: false}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OpenTelemetry trace exporter: %w", err)
+	}
+	metricExporter, err := otlpmetrichttp.New(ctx,
+		otlpmetrichttp.WithEndpointURL(base+otelMetricsPath),
+		otlpmetrichttp.WithTimeout(otelExportTimeout),
+		otlpmetrichttp.WithRetry(otlpmetrichttp.RetryConfig{Enabled: false}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OpenTelemetry metric exporter: %w", err)
+	}
+
+	res := resource.NewSchemaless(
+		attribute.String("service.name", otelServiceName),
+		attribute.String("benchmark.run_timestamp", runTimestamp),
+	)
+	status := &otelExportStatus{}
+	tracerProvider := sdktrace.NewTracerProvider(
+		sdktrace.WithResource(res),
+		sdktrace.WithBatcher(&otelSpanExporter{SpanExporter: traceExporter, status: status},
+			sdktrace.WithMaxQueueSize(metricsQueueCapacity),
+			sdktrace.WithMaxExportBatchSize(batchSize),
+			sdktrace.WithBatchTimeout(flushInterval),
+			sdktrace.WithExportTimeout(otelExportTimeout)),
+	)
+	meterProvider := sdkmetric.NewMeterProvider(
+		sdkmetric.WithResource(res),
+		sdkmetric.WithReader(sdkmetric.NewPeriodicReader(&otelMetricExporter{Exporter: metricExporter, status: status},
+			sdkmetric.WithInterval(flushInterval),
+			sdkmetric.WithTimeout(otelExportTimeout))),
+	)
+
+	latency, err := meterProvider.Meter(otelScopeName).Float64Histogram(otelHistogramName,
+		metric.WithUnit("ms"),
+		metric.WithExplicitBucketBoundaries(otelHistogramBoundsMs...))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OpenTelemetry latency histogram: %w", err)
+	}
+
+	return &OTelWriter{
+		tracerProvider: tracerProvider,
+		meterProvider:  meterProvider,
+		tracer:         tracerProvider.Tracer(otelScopeName),
+		latency:        latency,
+		status:         status,
+		done:           make(chan struct{}),
+		stopped:        make(chan struct{}),
+	}, nil
+}
+
+// WriteResult records a result as a span and a latency measurement. Neither
+// blocks: the batch span processor drops spans when its queue is full.
+func (w *OTelWriter) WriteResult(metrics *QueryExecutionMetrics) {
+	select {
+	case <-w.done:
+		atomic.AddInt64(&w.closedCount, 1)
+		logWarnf("Attempted to write to closed OpenTelemetry writer")
+		return
+	default:
+	}
+
+	start := time.UnixMilli(metrics.AbsoluteStartTimeMs)
+	attrs := []attribute.KeyValue{
+		attribute.String("sdk_type", metrics.SDKType),
+		attribute.String("query_name", metrics.QueryName),
+		attribute.Int("row_count", metrics.RowCount),
+		attribute.Bool("success", metrics.Success),
+		attribute.Int64("sequence_number", metrics.SequenceNumber),
+	}
+	if metrics.ClusterID != "" {
+		attrs = append(attrs, attribute.String("cluster_id", metrics.ClusterID))
+	}
+	if !metrics.Success && metrics.ErrorCategory != "" {
+		attrs = append(attrs, attribute.String("error_category", metrics.ErrorCategory))
+	}
+
+	// Every request is its own root span, so each gets a new trace
+	_, span := w.tracer.Start(context.Background(), otelSpanName,
+		trace.WithNewRoot(),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithTimestamp(start),
+		trace.WithAttributes(attrs...))
+	if !metrics.Success {
+		span.SetStatus(codes.Error, metrics.ErrorMessage)
+	}
+	span.End(trace.WithTimestamp(start.Add(time.Duration(metrics.DurationNanos))))
+	atomic.AddInt64(&w.submittedCount, 1)
+
+	w.latency.Record(context.Background(), metrics.DurationMs, metric.WithAttributes(
+		attribute.String("sdk_type", metrics.SDKType),
+		attribute.String("query_name", metrics.QueryName),
+		attribute.Bool("success", metrics.Success),
+	))
+}
+
+// Start waits until ctx is cancelled, then shuts down both providers, which
+// exports the spans still queued and the final histogram
+func (w *OTelWriter) Start(ctx context.Context) {
+	defer close(w.stopped)
+
+	<-ctx.Done()
+	close(w.done)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 2*otelExportTimeout)
+	defer cancel()
+	if err := w.tracerProvider.Shutdown(shutdownCtx); err != nil {
+		logWarnf("OpenTelemetry trace provider shutdown failed: %v", err)
+	}
+	if err := w.meterProvider.Shutdown(shutdownCtx); err != nil {
+		logWarnf("OpenTelemetry meter provider shutdown failed: %v", err)
+	}
+}
+
+func (w *OTelWriter) Wait() {
+	<-w.stopped
+}
+
+// GetWrittenCount returns the number of spans the collector accepted
+func (w *OTelWriter) GetWrittenCount() int64 {
+	return atomic.LoadInt64(&w.status.exportedSpans)
+}
+
+// GetDroppedCount returns the number of results lost to a failed export or a
+// closed writer. Once the writer has stopped it also counts the spans the batch
+// span processor dropped because its queue was full.
+func (w *OTelWriter) GetDroppedCount() int64 {
+	dropped := atomic.LoadInt64(&w.status.failedSpans) + atomic.LoadInt64(&w.closedCount)
+	if w.isStopped() {
+		dropped += w.pendingSpans()
+	}
+	return dropped
+}
+
+// GetQueueSize returns the number of spans recorded but not yet exported or dropped
+func (w *OTelWriter) GetQueueSize() int {
+	if w.isStopped() {
+		return 0
+	}
+	return int(w.pendingSpans())
+}
+
+// pendingSpans returns the number of recorded spans the exporter hasn't seen yet.
+// After shutdown these are the spans the batch span processor dropped.
+func (w *OTelWriter) pendingSpans() int64 {
+	return atomic.LoadInt64(&w.submittedCount) - atomic.LoadInt64(&w.status.exportedSpans) -
+		atomic.LoadInt64(&w.status.failedSpans)
+}
+
+func (w *OTelWriter) isStopped() bool {
+	select {
+	case <-w.stopped:
+		return true
+	default:
+		return false
+	}
+}
+
+// otelExportStatus counts exported spans and logs once per export outage
+type otelExportStatus struct {
+	exportedSpans int64
+	failedSpans   int64
+
+	mu      sync.Mutex
+	failing bool
+}
+
+func (s *otelExportStatus) report(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch {
+	case err != nil && !s.failing:
+		logWarnf("OpenTelemetry export failed, dropping batches until it recovers: %v", err)
+		s.failing = true
+	case err == nil && s.failing:
+		log.Printf("OpenTelemetry export recovered")
+		s.failing = false
+	}
+}
+
+// otelSpanExporter counts the spans each export accepted or dropped. Failures are
+// reported here rather than returned, so the SDK doesn't log every failed batch.
+type otelSpanExporter struct {
+	sdktrace.SpanExporter
+	status *otelExportStatus
+}
+
+func (e *otelSpanExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	err := e.SpanExporter.ExportSpans(ctx, spans)
+	if err != nil {
+		atomic.AddInt64(&e.status.failedSpans, int64(len(spans)))
+	} else {
+		atomic.AddInt64(&e.status.exportedSpans, int64(len(spans)))
+	}
+	e.status.report(err)
+	return nil
+}
+
+// otelMetricExporter reports histogram export failures alongside span ones
+type otelMetricExporter struct {
+	sdkmetric.Exporter
+	status *otelExportStatus
+}
+
+func (e *otelMetricExporter) Export(ctx context.Context, rm *metricdata.ResourceMetrics) error {
+	e.status.report(e.Exporter.Export(ctx, rm))
+	return nil
+}
@@ -0,0 +1,91 @@
+package benchmark
+
+import (
+	"container/heap"
+	"sort"
+	"sync"
+)
+
+// Outlier is one of the slowest requests of the run, with what is needed to find
+// it in the raw output and the server logs
+type Outlier struct {
+	SequenceNumber      int64   `json:"sequence_number"`
+	QueryName           string  `json:"query_name"`
+	DurationMs          float64 `json:"duration_ms"`
+	AbsoluteStartTimeMs int64   `json:"absolute_start_time_ms"`
+	Success             bool    `json:"success"`
+	ClusterID           string  `json:"cluster_id,omitempty"`
+}
+
+// OutlierSummary is the slowest requests, slowest first, and how many requests
+// took longer than the outlier threshold
+type OutlierSummary struct {
+	ThresholdMs   float64   `json:"threshold_ms,omitempty"`
+	OverThreshold int64     `json:"over_threshold"`
+	Slowest       []Outlier `json:"slowest"`
+}
+
+// outlierHeap is a min-heap on duration, so the fastest of the kept requests is
+// the one evicted
+type outlierHeap []Outlier
+
+func (h outlierHeap) Len() int           { return len(h) }
+func (h outlierHeap) Less(i, j int) bool { return h[i].DurationMs < h[j].DurationMs }
+func (h outlierHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *outlierHeap) Push(x any)        { *h = append(*h, x.(Outlier)) }
+func (h *outlierHeap) Pop() any {
+	old := *h
+	last := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return last
+}
+
+// OutlierStats keeps the N slowest requests, successful or not, in bounded memory
+// and counts the requests over a latency threshold
+type OutlierStats struct {
+	mu          sync.Mutex
+	topN        int
+	thresholdMs float64
+	over        int64
+	slowest     outlierHeap
+}
+
+// NewOutlierStats tracks the topN slowest requests; a thresholdMs of 0 disables
+// the threshold count
+func NewOutlierStats(topN int, thresholdMs float64) *OutlierStats {
+	return &OutlierStats{topN: topN, thresholdMs: thresholdMs}
+}
+
+// Add records one completed request
+func (s *OutlierStats) Add(result *QueryExecutionMetrics) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.thresholdMs > 0 && result.DurationMs > s.thresholdMs {
+		s.over++
+	}
+	if s.topN <= 0 || (len(s.slowest) == s.topN && result.DurationMs <= s.slowest[0].DurationMs) {
+		return
+	}
+	heap.Push(&s.slowest, Outlier{
+		SequenceNumber:      result.SequenceNumber,
+		QueryName:           result.QueryName,
+		DurationMs:          result.DurationMs,
+		AbsoluteStartTimeMs: result.AbsoluteStartTimeMs,
+		Success:             result.Success,
+		ClusterID:           result.ClusterID,
+	})
+	if len(s.slowest) > s.topN {
+		heap.Pop(&s.slowest)
+	}
+}
+
+// Summary returns the slowest requests, slowest first, and the threshold count
+func (s *OutlierStats) Summary() OutlierSummary {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	slowest := append([]Outlier(nil), s.slowest...)
+	sort.Slice(slowest, func(i, j int) bool { return slowest[i].DurationMs > slowest[j].DurationMs })
+	return OutlierSummary{ThresholdMs: s.thresholdMs, OverThreshold: s.over, Slowest: slowest}
+}
@@ -0,0 +1,129 @@
+package benchmark
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// QueryParameters are the positional and named parameters sent with one request
+type QueryParameters struct {
+	Positional []interface{}          `json:"positional,omitempty"`
+	Named      map[string]interface{} `json:"named,omitempty"`
+}
+
+// IsEmpty reports whether no parameters are sent
+func (p QueryParameters) IsEmpty() bool {
+	return len(p.Positional) == 0 && len(p.Named) == 0
+}
+
+// paramGenerator produces a parameter value for one request
+type paramGenerator func(seq int64, rng *rand.Rand) interface{}
+
+// ParamsTemplate renders per-request query parameters from a JSON template: an
+// array for positional parameters or an object for named ones. Top-level string
+// values starting with "$" are generators evaluated for every request:
+//
+//	"$seq"           the request's sequence number
+//	"$rand:MIN:MAX"  a random integer in [MIN, MAX]
+//	"$now"           the current time in RFC 3339
+//	"$now-DURATION"  the current time minus a Go duration, e.g. "$now-24h"
+//
+// All other values are sent as-is.
+type ParamsTemplate struct {
+	positional []paramGenerator
+	names      []string
+	named      []paramGenerator
+}
+
+// ParseParamsTemplate parses a parameter template, returning nil for an empty spec
+func ParseParamsTemplate(spec string) (*ParamsTemplate, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return nil, nil
+	}
+
+	template := &ParamsTemplate{}
+	switch spec[0] {
+	case '[':
+		var values []interface{}
+		if err := json.Unmarshal([]byte(spec), &values); err != nil {
+			return nil, fmt.Errorf("invalid query params: %w", err)
+		}
+		for i, value := range values {
+			generator, err := newParamGenerator(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid query param %d: %w", i+1, err)
+			}
+			template.positional = append(template.positional, generator)
+		}
+	case '{':
+		var values map[string]interface{}
+		if err := json.Unmarshal([]byte(spec), &values); err != nil {
+			return nil, fmt.Errorf("invalid query params: %w", err)
+		}
+		for name, value := range values {
+			generator, err := newParamGenerator(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid query param %s: %w", name, err)
+			}
+			template.names = append(template.names, name)
+			template.named = append(template.named, generator)
+		}
+	default:
+		return nil, fmt.Errorf("invalid query params: expected a JSON array or object")
+	}
+	return template, nil
+}
+
+// newParamGenerator returns a generator for a template value
+func newParamGenerator(value interface{}) (paramGenerator, error) {
+	spec, ok := value.(string)
+	if !ok || !strings.HasPrefix(spec, "$") {
+		return func(int64, *rand.Rand) interface{} { return value }, nil
+	}
+
+	switch {
+	case spec == "$seq":
+		return func(seq int64, _ *rand.Rand) interface{} { return seq }, nil
+	case spec == "$now":
+		return func(int64, *rand.Rand) interface{} { return time.Now().Format(time.RFC3339) }, nil
+	case strings.HasPrefix(spec, "$now-"):
+		offset, err := time.ParseDuration(strings.TrimPrefix(spec, "$now-"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid duration in %q", spec)
+		}
+		return func(int64, *rand.Rand) interface{} { return time.Now().Add(-offset).Format(time.RFC3339) }, nil
+	case strings.HasPrefix(spec, "$rand:"):
+		parts := strings.Split(strings.TrimPrefix(spec, "$rand:"), ":")
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid generator %q (expected $rand:MIN:MAX)", spec)
+		}
+		min, errMin := strconv.ParseInt(parts[0], 10, 64)
+		max, errMax := strconv.ParseInt(parts[1], 10, 64)
+		if errMin != nil || errMax != nil || max < min {
+			return nil, fmt.Errorf("invalid range in %q", spec)
+		}
+		return func(_ int64, rng *rand.Rand) interface{} { return min + rng.Int63n(max-min+1) }, nil
+	default:
+		return nil, fmt.Errorf("unknown generator %q", spec)
+	}
+}
+
+// Render evaluates the template for one request
+func (t *ParamsTemplate) Render(seq int64, rng *rand.Rand) QueryParameters {
+	var params QueryParameters
+	for _, generator := range t.positional {
+		params.Positional = append(params.Positional, generator(seq, rng))
+	}
+	if len(t.named) > 0 {
+		params.Named = make(map[string]interface{}, len(t.named))
+		for i, generator := range t.named {
+			params.Named[t.names[i]] = generator(seq, rng)
+		}
+	}
+	return params
+}
@@ -0,0 +1,128 @@
+package benchmark
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// PhaseTimings splits a request's client-observed latency into the phases the
+// server metadata lets us distinguish
+type PhaseTimings struct {
+	ServerQueueAndPlanMs float64 `json:"server_queue_and_plan_ms"`
+	ServerExecutionMs    float64 `json:"server_execution_ms"`
+	NetworkAndStreamMs   float64 `json:"network_and_stream_ms"`
+}
+
+// NewPhaseTimings derives phase timings from the client total and the server-reported
+// elapsed and execution times. Negative remainders from clock skew are clamped to zero.
+func NewPhaseTimings(clientTotal, serverElapsed, serverExecution time.Duration) *PhaseTimings {
+	toMs := func(d time.Duration) float64 {
+		if d < 0 {
+			return 0
+		}
+		return float64(d.Nanoseconds()) / 1_000_000.0
+	}
+
+	return &PhaseTimings{
+		ServerQueueAndPlanMs: toMs(serverElapsed - serverExecution),
+		ServerExecutionMs:    toMs(serverExecution),
+		NetworkAndStreamMs:   toMs(clientTotal - serverElapsed),
+	}
+}
+
+// PhaseStats aggregates phase timings per query name
+type PhaseStats struct {
+	mu     sync.Mutex
+	totals map[string]*PhaseTimings
+	counts map[string]int64
+}
+
+// NewPhaseStats creates an empty phase aggregator
+func NewPhaseStats() *PhaseStats {
+	return &PhaseStats{
+		totals: make(map[string]*PhaseTimings),
+		counts: make(map[string]int64),
+	}
+}
+
+// Add records the phases of one request
+func (s *PhaseStats) Add(queryName string, phases *PhaseTimings) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	total, ok := s.totals[queryName]
+	if !ok {
+		total = &PhaseTimings{}
+		s.totals[queryName] = total
+	}
+	total.ServerQueueAndPlanMs += phases.ServerQueueAndPlanMs
+	total.ServerExecutionMs += phases.ServerExecutionMs
+	total.NetworkAndStreamMs += phases.NetworkAndStreamMs
+	s.counts[queryName]++
+}
+
+// Averages returns the average phase timings across all query names and the sample count
+func (s *PhaseStats) Averages() (PhaseTimings, int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var sum PhaseTimings
+	var count int64
+	for name, total := range s.totals {
+		sum.ServerQueueAndPlanMs += total.ServerQueueAndPlanMs
+		sum.ServerExecutionMs += total.ServerExecutionMs
+		sum.NetworkAndStreamMs += total.NetworkAndStreamMs
+		count += s.counts[name]
+	}
+	if count == 0 {
+		return PhaseTimings{}, 0
+	}
+
+	n := float64(count)
+	return PhaseTimings{
+		ServerQueueAndPlanMs: sum.ServerQueueAndPlanMs / n,
+		ServerExecutionMs:    sum.ServerExecutionMs / n,
+		NetworkAndStreamMs:   sum.NetworkAndStreamMs / n,
+	}, count
+}
+
+// WriteFolded writes the aggregated phases in collapsed-stack format
+// ("frame;frame value", values in microseconds), which flamegraph.pl and
+// speedscope render directly
+func (s *PhaseStats) WriteFolded(path string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	names := make([]string, 0, len(s.totals))
+	for name := range s.totals {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		total := s.totals[name]
+		lines := []struct {
+			stack string
+			ms    float64
+		}{
+			{"server;queue_and_plan", total.ServerQueueAndPlanMs},
+			{"server;execution", total.ServerExecutionMs},
+			{"client;network_and_stream", total.NetworkAndStreamMs},
+		}
+		for _, line := range lines {
+			if _, err := fmt.Fprintf(file, "%s;%s %d\n", name, line.stack, int64(line.ms*1000)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
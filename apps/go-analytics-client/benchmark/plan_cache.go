@@ -0,0 +1,99 @@
+package benchmark
+
+import (
+	"fmt"
+	"sync"
+)
+
+// planVariantQuery wraps query so each variant is a structurally distinct statement
+// the server must compile separately. SELECT VALUE returns each row unchanged and
+// the always-true predicate carries the variant number as a literal.
+func planVariantQuery(query string, variant int) string {
+	return fmt.Sprintf("SELECT VALUE plan_variant FROM (%s) AS plan_variant WHERE %d = %d", query, variant, variant)
+}
+
+// cacheBustingQuery prefixes query with a comment unique to the run and request,
+// so no two requests send the same statement text and a cache keyed on it never
+// hits
+func cacheBustingQuery(query string, runNonce, seq int64) string {
+	return fmt.Sprintf("/* nonce %d-%d */ %s", runNonce, seq, query)
+}
+
+// PlanCacheStats compares the first execution of each query variant, which must be
+// compiled, with repeat executions that can reuse a cached plan
+type PlanCacheStats struct {
+	mu   sync.Mutex
+	seen map[int]bool
+
+	firstCount     int64
+	firstLatencyMs float64
+	firstPlanCount int64
+	firstPlanMs    float64
+
+	repeatCount     int64
+	repeatLatencyMs float64
+	repeatPlanCount int64
+	repeatPlanMs    float64
+}
+
+// NewPlanCacheStats creates an empty plan-cache aggregator
+func NewPlanCacheStats() *PlanCacheStats {
+	return &PlanCacheStats{seen: make(map[int]bool)}
+}
+
+// Add records one successful request of a query variant
+func (s *PlanCacheStats) Add(result *QueryExecutionMetrics) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.seen[result.QueryVariant] {
+		s.seen[result.QueryVariant] = true
+		s.firstCount++
+		s.firstLatencyMs += result.DurationMs
+		if result.Phases != nil {
+			s.firstPlanCount++
+			s.firstPlanMs += result.Phases.ServerQueueAndPlanMs
+		}
+		return
+	}
+
+	s.repeatCount++
+	s.repeatLatencyMs += result.DurationMs
+	if result.Phases != nil {
+		s.repeatPlanCount++
+		s.repeatPlanMs += result.Phases.ServerQueueAndPlanMs
+	}
+}
+
+// PlanCacheSummary is the first-versus-repeat breakdown reported at the end of a run
+type PlanCacheSummary struct {
+	DistinctQueries int64
+	FirstAvgMs      float64
+	RepeatAvgMs     float64
+	RepeatCount     int64
+	FirstPlanAvgMs  float64
+	RepeatPlanAvgMs float64
+	HasPlanTimings  bool
+}
+
+// Summary returns the distinct-query count and average latencies for first and repeat executions
+func (s *PlanCacheStats) Summary() PlanCacheSummary {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	avg := func(total float64, count int64) float64 {
+		if count == 0 {
+			return 0
+		}
+		return total / float64(count)
+	}
+	return PlanCacheSummary{
+		DistinctQueries: int64(len(s.seen)),
+		FirstAvgMs:      avg(s.firstLatencyMs, s.firstCount),
+		RepeatAvgMs:     avg(s.repeatLatencyMs, s.repeatCount),
+		RepeatCount:     s.repeatCount,
+		FirstPlanAvgMs:  avg(s.firstPlanMs, s.firstPlanCount),
+		RepeatPlanAvgMs: avg(s.repeatPlanMs, s.repeatPlanCount),
+		HasPlanTimings:  s.firstPlanCount > 0,
+	}
+}
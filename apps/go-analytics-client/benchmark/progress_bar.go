@@ -0,0 +1,117 @@
+package benchmark
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// progressBarWidth is the number of cells in the bar itself
+const progressBarWidth = 30
+
+// stderrTerminal is stderr as the logger installed by SetupLogging and the
+// progress bar share it
+var stderrTerminal = &terminalWriter{out: os.Stderr}
+
+// terminalWriter writes log lines to a terminal that may be showing a progress
+// bar. While one is shown, every write clears the bar first and redraws it after,
+// so log lines never land on the bar's line.
+type terminalWriter struct {
+	mu  sync.Mutex
+	out *os.File
+	bar string
+}
+
+func (t *terminalWriter) Write(p []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.bar != "" {
+		fmt.Fprint(t.out, "\r\033[K")
+	}
+	n, err := t.out.Write(p)
+	if t.bar != "" {
+		fmt.Fprint(t.out, t.bar)
+	}
+	return n, err
+}
+
+// draw replaces the bar on the terminal's last line
+func (t *terminalWriter) draw(bar string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.bar = bar
+	fmt.Fprint(t.out, "\r\033[K", bar)
+}
+
+// release leaves the last bar on its own line and stops redrawing it
+func (t *terminalWriter) release() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.bar != "" {
+		fmt.Fprintln(t.out)
+		t.bar = ""
+	}
+}
+
+// stderrIsTerminal reports whether stderr is an interactive terminal rather than
+// a pipe or file, e.g. under CI
+func stderrIsTerminal() bool {
+	info, err := os.Stderr.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// ProgressBar shows the measurement's progress as a single self-overwriting line
+// on stderr, in place of the periodic progress log lines
+type ProgressBar struct {
+	total time.Duration
+
+	mu       sync.Mutex
+	finished bool
+}
+
+// NewProgressBar creates a bar for a measurement lasting total
+func NewProgressBar(total time.Duration) *ProgressBar {
+	return &ProgressBar{total: total}
+}
+
+// Update redraws the bar with the elapsed time, the requests so far, and the
+// last interval's throughput and p99; a negative p99 means the interval had no
+// completions. Updates after Finish are ignored.
+func (b *ProgressBar) Update(elapsed time.Duration, requests int64, rps, p99Ms float64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.finished {
+		return
+	}
+
+	fraction := 1.0
+	if b.total > 0 && elapsed < b.total {
+		fraction = float64(elapsed) / float64(b.total)
+	}
+	filled := int(fraction * progressBarWidth)
+	p99 := "p99 -"
+	if p99Ms >= 0 {
+		p99 = fmt.Sprintf("p99 %.2fms", p99Ms)
+	}
+	stderrTerminal.draw(fmt.Sprintf("⏱  [%s%s] %3.0f%% %ds/%ds | %d requests | %.2f RPS | %s",
+		strings.Repeat("█", filled), strings.Repeat("░", progressBarWidth-filled), fraction*100,
+		int(elapsed.Seconds()), int(b.total.Seconds()), requests, rps, p99))
+}
+
+// Finish leaves the last bar on screen and hands stderr back to plain logging.
+// It is safe to call on a nil bar.
+func (b *ProgressBar) Finish() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.finished = true
+	stderrTerminal.release()
+}
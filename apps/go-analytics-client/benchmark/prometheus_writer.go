@@ -0,0 +1,120 @@
+package benchmark
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/push"
+)
+
+const (
+	prometheusJobName     = "analytics_performance_tester"
+	prometheusPushTimeout = 5 * time.Second
+)
+
+// PrometheusWriter exposes live latency and throughput by pushing a registry of
+// histogram and counter vectors to a Prometheus pushgateway. Results update the
+// vectors in place, so pushing never holds up the workers, and a gateway that is
+// unreachable only costs a log line per outage.
+type PrometheusWriter struct {
+	pusher   *push.Pusher
+	interval time.Duration
+	duration *prometheus.HistogramVec
+	queries  *prometheus.CounterVec
+
+	writtenCount int64
+	done         chan struct{}
+}
+
+// NewPrometheusWriter creates a writer pushing to gatewayURL every interval,
+// grouped under the run timestamp so concurrent runs do not overwrite each other
+func NewPrometheusWriter(gatewayURL, runTimestamp string, interval time.Duration) *PrometheusWriter {
+	duration := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "analytics_query_duration_seconds",
+		Help:    "Client-observed analytics query latency.",
+		Buckets: prometheus.ExponentialBuckets(0.001, 2, 16),
+	}, []string{"sdk_type", "query_name"})
+	queries := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "analytics_queries_total",
+		Help: "Completed analytics queries by outcome.",
+	}, []string{"sdk_type", "query_name", "outcome"})
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(duration, queries)
+
+	return &PrometheusWriter{
+		pusher: push.New(gatewayURL, prometheusJobName).
+			Gatherer(registry).
+			Grouping("run", runTimestamp).
+			Client(&http.Client{Timeout: prometheusPushTimeout}),
+		interval: interval,
+		duration: duration,
+		queries:  queries,
+		done:     make(chan struct{}),
+	}
+}
+
+// WriteResult records a result in the metric vectors
+func (w *PrometheusWriter) WriteResult(metrics *QueryExecutionMetrics) {
+	outcome := "success"
+	if !metrics.Success {
+		outcome = "failure"
+	}
+	w.duration.WithLabelValues(metrics.SDKType, metrics.QueryName).Observe(metrics.DurationMs / 1000.0)
+	w.queries.WithLabelValues(metrics.SDKType, metrics.QueryName, outcome).Inc()
+	atomic.AddInt64(&w.writtenCount, 1)
+}
+
+// Start pushes on every interval until ctx is cancelled, then pushes once more
+// so the gateway holds the final totals
+func (w *PrometheusWriter) Start(ctx context.Context) {
+	defer close(w.done)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	failing := false
+	pushMetrics := func() {
+		err := w.pusher.Push()
+		switch {
+		case err != nil && !failing:
+			logWarnf("Pushgateway push failed, continuing without live metrics: %v", err)
+			failing = true
+		case err == nil && failing:
+			log.Printf("Pushgateway push recovered")
+			failing = false
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			pushMetrics()
+			return
+		case <-ticker.C:
+			pushMetrics()
+		}
+	}
+}
+
+func (w *PrometheusWriter) Wait() {
+	<-w.done
+}
+
+func (w *PrometheusWriter) GetWrittenCount() int64 {
+	return atomic.LoadInt64(&w.writtenCount)
+}
+
+// GetDroppedCount is always zero since results are recorded synchronously
+func (w *PrometheusWriter) GetDroppedCount() int64 {
+	return 0
+}
+
+// GetQueueSize is always zero since results are recorded synchronously
+func (w *PrometheusWriter) GetQueueSize() int {
+	return 0
+}
@@ -0,0 +1,48 @@
+package benchmark
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/couchbase/gocb/v2"
+	cbanalytics "github.com/couchbase/gocbanalytics"
+)
+
+// operationalQuerier runs analytics queries with the operational SDK; both a
+// cluster and a scope do, the scope resolving unqualified names against itself
+type operationalQuerier interface {
+	AnalyticsQuery(statement string, opts *gocb.AnalyticsOptions) (*gocb.AnalyticsResult, error)
+}
+
+// enterpriseQuerier runs analytics queries with the enterprise SDK; both a
+// cluster and a scope do, the scope resolving unqualified names against itself
+type enterpriseQuerier interface {
+	ExecuteQuery(ctx context.Context, statement string, opts ...*cbanalytics.QueryOptions) (*cbanalytics.QueryResult, error)
+}
+
+// validateQueryContext checks the query context bucket and scope are set together
+func validateQueryContext(config Configuration) error {
+	if (config.QueryContextBucket == "") != (config.QueryContextScope == "") {
+		return fmt.Errorf("BENCHMARK_QUERY_CONTEXT_BUCKET and BENCHMARK_QUERY_CONTEXT_SCOPE must be set together")
+	}
+	return nil
+}
+
+// operationalQuerierFor returns the configured query context's scope on the
+// cluster, or the cluster itself when no query context is set
+func operationalQuerierFor(cluster *gocb.Cluster, config Configuration) operationalQuerier {
+	if config.QueryContextBucket == "" {
+		return cluster
+	}
+	return cluster.Bucket(config.QueryContextBucket).Scope(config.QueryContextScope)
+}
+
+// enterpriseQuerierFor returns the configured query context's scope on the
+// cluster, or the cluster itself when no query context is set. The enterprise
+// SDK calls the bucket a database.
+func enterpriseQuerierFor(cluster *cbanalytics.Cluster, config Configuration) enterpriseQuerier {
+	if config.QueryContextBucket == "" {
+		return cluster
+	}
+	return cluster.Database(config.QueryContextBucket).Scope(config.QueryContextScope)
+}
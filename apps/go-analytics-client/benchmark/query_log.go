@@ -0,0 +1,134 @@
+package benchmark
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// queryLogQueueCapacity is how many parsed queries the reader keeps ahead of the
+// workers; the rest of the file stays on disk
+const queryLogQueueCapacity = 1024
+
+// queryLogQueryName is the query name recorded for requests from a query log
+const queryLogQueryName = "query_log"
+
+// QueryLogEntry is one query read from a query log file
+type QueryLogEntry struct {
+	Query string
+	// Offset is when to issue the query relative to the start of the measurement;
+	// only meaningful when HasOffset is set
+	Offset    time.Duration
+	HasOffset bool
+	Line      int
+}
+
+// QueryLogSource streams a recorded workload to the workers in file order. Each
+// line is a query, optionally preceded by its offset in milliseconds from the
+// start of the workload and a tab. Blank lines and lines starting with # are
+// skipped. Only a bounded number of queries is held in memory, so the file can be
+// far larger than RAM.
+type QueryLogSource struct {
+	path    string
+	entries chan QueryLogEntry
+
+	mu  sync.Mutex
+	err error
+}
+
+// OpenQueryLogSource checks the query log file can be read
+func OpenQueryLogSource(path string) (*QueryLogSource, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open query log: %w", err)
+	}
+	file.Close()
+	return &QueryLogSource{
+		path:    path,
+		entries: make(chan QueryLogEntry, queryLogQueueCapacity),
+	}, nil
+}
+
+// Start reads the file and queues its queries until the file ends or ctx is
+// cancelled. The queue is closed when reading stops.
+func (s *QueryLogSource) Start(ctx context.Context) {
+	defer close(s.entries)
+
+	file, err := os.Open(s.path)
+	if err != nil {
+		s.setErr(fmt.Errorf("failed to open query log: %w", err))
+		return
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		entry, ok, err := parseQueryLogLine(scanner.Text(), lineNumber)
+		if err != nil {
+			s.setErr(err)
+			return
+		}
+		if !ok {
+			continue
+		}
+		select {
+		case s.entries <- entry:
+		case <-ctx.Done():
+			return
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		s.setErr(fmt.Errorf("failed to read query log: %w", err))
+	}
+}
+
+// parseQueryLogLine parses one line, reporting false for lines without a query
+func parseQueryLogLine(line string, lineNumber int) (QueryLogEntry, bool, error) {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return QueryLogEntry{}, false, nil
+	}
+
+	entry := QueryLogEntry{Query: line, Line: lineNumber}
+	if offset, query, found := strings.Cut(line, "\t"); found {
+		if ms, err := strconv.ParseInt(strings.TrimSpace(offset), 10, 64); err == nil {
+			if ms < 0 {
+				return entry, false, fmt.Errorf("negative offset on query log line %d", lineNumber)
+			}
+			entry.Query = strings.TrimSpace(query)
+			entry.Offset = time.Duration(ms) * time.Millisecond
+			entry.HasOffset = true
+		}
+	}
+	if entry.Query == "" {
+		return entry, false, fmt.Errorf("missing query on query log line %d", lineNumber)
+	}
+	return entry, true, nil
+}
+
+// Next returns the next query in file order, or false once the file is exhausted
+func (s *QueryLogSource) Next() (QueryLogEntry, bool) {
+	entry, ok := <-s.entries
+	return entry, ok
+}
+
+// Err returns the error that stopped reading early, if any
+func (s *QueryLogSource) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+func (s *QueryLogSource) setErr(err error) {
+	s.mu.Lock()
+	s.err = err
+	s.mu.Unlock()
+}
@@ -0,0 +1,179 @@
+package benchmark
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// QueryMixEntry is one query in a weighted mix
+type QueryMixEntry struct {
+	Name      string  `json:"name" yaml:"name"`
+	Query     string  `json:"query" yaml:"query"`
+	Weight    float64 `json:"weight" yaml:"weight"`
+	TimeoutMs int64   `json:"timeout_ms,omitempty" yaml:"timeout_ms"`
+}
+
+// Timeout returns the entry's timeout, or zero to use the global analytics timeout
+func (e QueryMixEntry) Timeout() time.Duration {
+	return time.Duration(e.TimeoutMs) * time.Millisecond
+}
+
+// QueryMix picks queries in proportion to their weights
+type QueryMix struct {
+	entries    []QueryMixEntry
+	cumulative []float64
+}
+
+// LoadQueryMix reads a JSON array of query mix entries
+func LoadQueryMix(path string) (*QueryMix, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read query mix file: %w", err)
+	}
+
+	var entries []QueryMixEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("invalid query mix file: %w", err)
+	}
+	return NewQueryMix(entries)
+}
+
+// NewQueryMix validates the entries and prepares weighted selection
+func NewQueryMix(entries []QueryMixEntry) (*QueryMix, error) {
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("query mix has no entries")
+	}
+
+	mix := &QueryMix{entries: entries, cumulative: make([]float64, len(entries))}
+	total := 0.0
+	for i, entry := range entries {
+		if entry.Name == "" || entry.Query == "" {
+			return nil, fmt.Errorf("query mix entry %d needs a name and a query", i+1)
+		}
+		if entry.Weight <= 0 {
+			return nil, fmt.Errorf("query mix entry %q needs a positive weight", entry.Name)
+		}
+		if entry.TimeoutMs < 0 {
+			return nil, fmt.Errorf("query mix entry %q has a negative timeout", entry.Name)
+		}
+		total += entry.Weight
+		mix.cumulative[i] = total
+	}
+	return mix, nil
+}
+
+// Pick returns a query chosen by weight
+func (m *QueryMix) Pick(rng *rand.Rand) QueryMixEntry {
+	target := rng.Float64() * m.cumulative[len(m.cumulative)-1]
+	index := sort.SearchFloat64s(m.cumulative, target)
+	if index >= len(m.entries) {
+		index = len(m.entries) - 1
+	}
+	return m.entries[index]
+}
+
+// Entries returns the queries in the mix
+func (m *QueryMix) Entries() []QueryMixEntry {
+	return m.entries
+}
+
+// MaxTimeout returns the longest timeout any entry runs with, where entries
+// without their own use defaultTimeout
+func (m *QueryMix) MaxTimeout(defaultTimeout time.Duration) time.Duration {
+	longest := time.Duration(0)
+	for _, entry := range m.entries {
+		timeout := entry.Timeout()
+		if timeout <= 0 {
+			timeout = defaultTimeout
+		}
+		if timeout > longest {
+			longest = timeout
+		}
+	}
+	return longest
+}
+
+// QueryNameStats keeps request outcomes and latencies per query name, or per
+// another label of the result
+type QueryNameStats struct {
+	mu     sync.Mutex
+	digits int
+	key    func(result *QueryExecutionMetrics) string
+	names  []string
+	byName map[string]*queryNameLatencies
+}
+
+type queryNameLatencies struct {
+	success *LatencyRecorder
+	failure *LatencyRecorder
+}
+
+// NewQueryNameStats creates an empty per-query-name collector
+func NewQueryNameStats(significantDigits int) *QueryNameStats {
+	return &QueryNameStats{
+		digits: significantDigits,
+		key:    func(result *QueryExecutionMetrics) string { return result.QueryName },
+		byName: make(map[string]*queryNameLatencies),
+	}
+}
+
+// NewClusterStats creates an empty per-cluster collector; its summaries are
+// named by cluster ID
+func NewClusterStats(significantDigits int) *QueryNameStats {
+	return &QueryNameStats{
+		digits: significantDigits,
+		key:    func(result *QueryExecutionMetrics) string { return result.ClusterID },
+		byName: make(map[string]*queryNameLatencies),
+	}
+}
+
+// Add records one result under its query name, or other label
+func (s *QueryNameStats) Add(result *QueryExecutionMetrics) {
+	name := s.key(result)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	latencies, ok := s.byName[name]
+	if !ok {
+		latencies = &queryNameLatencies{
+			success: NewLatencyRecorder(s.digits),
+			failure: NewLatencyRecorder(s.digits),
+		}
+		s.byName[name] = latencies
+		s.names = append(s.names, name)
+	}
+	if result.Success {
+		latencies.success.Record(result.DurationMs)
+	} else {
+		latencies.failure.Record(result.DurationMs)
+	}
+}
+
+// Summaries returns one summary per query name, in the order names were first seen
+func (s *QueryNameStats) Summaries(calculator PercentileCalculator) []QuerySummary {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	summaries := make([]QuerySummary, 0, len(s.names))
+	for _, name := range s.names {
+		latencies := s.byName[name]
+		successes, failures := latencies.success.Count(), latencies.failure.Count()
+		total := successes + failures
+		summaries = append(summaries, QuerySummary{
+			Name:          name,
+			TotalRequests: total,
+			Successes:     successes,
+			Failures:      failures,
+			SuccessRate:   float64(successes) * 100.0 / float64(total),
+			Latency: NewLatencyPercentiles(calculator.Calculate(
+				latencies.success, latencies.failure, summaryPercentiles...)),
+		})
+	}
+	return summaries
+}
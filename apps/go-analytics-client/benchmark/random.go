@@ -0,0 +1,17 @@
+package benchmark
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+)
+
+// rngFor returns the random source for one consumer of randomness, such as a
+// worker. Each stream is derived from the run seed and the consumer's name and
+// index, so consumers are independent of each other and of how many there are,
+// and two runs with the same seed make the same random choices.
+func (r *SimpleAnalyticsRunner) rngFor(stream string, id int) *rand.Rand {
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%d/%s/%d", r.seed, stream, id)
+	return rand.New(rand.NewSource(int64(h.Sum64())))
+}
@@ -0,0 +1,125 @@
+package benchmark
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// ReconnectingSDKHandler gives a single worker its own connection and closes and
+// re-opens it every N requests to model aggressive connection recycling.
+// It is not safe for concurrent use.
+type ReconnectingSDKHandler struct {
+	factory  func() (AnalyticsSDKHandler, error)
+	every    int
+	sdkType  string
+	handler  AnalyticsSDKHandler
+	requests int
+	lastErr  error
+
+	// Reconnect cost to attach to the first request on the new connection
+	pendingReconnect time.Duration
+	reconnected      bool
+}
+
+// NewReconnectingSDKHandler opens the worker's initial connection
+func NewReconnectingSDKHandler(factory func() (AnalyticsSDKHandler, error), every int, sdkType string) (*ReconnectingSDKHandler, error) {
+	handler, err := factory()
+	if err != nil {
+		return nil, err
+	}
+
+	return &ReconnectingSDKHandler{
+		factory: factory,
+		every:   every,
+		sdkType: sdkType,
+		handler: handler,
+	}, nil
+}
+
+// ExecuteQuery recycles the connection when due, then executes the query on it
+func (h *ReconnectingSDKHandler) ExecuteQuery(ctx context.Context, query, queryName string, sequenceNumber int64, timeout time.Duration, params QueryParameters) *QueryExecutionMetrics {
+	if h.requests >= h.every || h.handler == nil {
+		h.reconnect()
+	}
+
+	if h.handler == nil {
+		now := time.Now()
+		metrics := NewQueryExecutionMetrics(
+			now, now, false, fmt.Sprintf("reconnect failed: %v", h.lastErr), 0,
+			h.sdkType, queryName, sequenceNumber, now.UnixMilli(),
+		)
+		metrics.ErrorCategory = ErrorCategoryConnection
+		return metrics
+	}
+
+	h.requests++
+	result := h.handler.ExecuteQuery(ctx, query, queryName, sequenceNumber, timeout, params)
+
+	if h.reconnected {
+		result.AfterReconnect = true
+		result.ReconnectMs = float64(h.pendingReconnect.Nanoseconds()) / 1_000_000.0
+		h.reconnected = false
+	}
+
+	return result
+}
+
+// FetchRows executes a query on the worker's current connection
+func (h *ReconnectingSDKHandler) FetchRows(query string) ([]json.RawMessage, error) {
+	if h.handler == nil {
+		return nil, fmt.Errorf("reconnect failed: %v", h.lastErr)
+	}
+	return h.handler.FetchRows(query)
+}
+
+// TraceQuery traces a query on the worker's current connection
+func (h *ReconnectingSDKHandler) TraceQuery(query string, params QueryParameters, canary int64) *CanaryTrace {
+	if h.handler == nil {
+		return &CanaryTrace{
+			TimestampMs:  time.Now().UnixMilli(),
+			SDKType:      h.sdkType,
+			Query:        query,
+			ErrorMessage: fmt.Sprintf("reconnect failed: %v", h.lastErr),
+		}
+	}
+	return h.handler.TraceQuery(query, params, canary)
+}
+
+// reconnect closes the current connection and opens a new one, timing the whole cycle
+func (h *ReconnectingSDKHandler) reconnect() {
+	start := time.Now()
+
+	if h.handler != nil {
+		if err := h.handler.Close(); err != nil {
+			logErrorf("Failed to close connection before reconnect: %v", err)
+		}
+		h.handler = nil
+	}
+
+	handler, err := h.factory()
+	if err != nil {
+		logWarnf("Reconnect failed: %v", err)
+		h.lastErr = err
+		return
+	}
+
+	h.handler = handler
+	h.requests = 0
+	h.pendingReconnect = time.Since(start)
+	h.reconnected = true
+}
+
+// GetSDKType returns the SDK type
+func (h *ReconnectingSDKHandler) GetSDKType() string {
+	return h.sdkType
+}
+
+// Close closes the worker's current connection
+func (h *ReconnectingSDKHandler) Close() error {
+	if h.handler != nil {
+		return h.handler.Close()
+	}
+	return nil
+}
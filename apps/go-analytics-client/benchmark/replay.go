@@ -0,0 +1,92 @@
+package benchmark
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"sync/atomic"
+)
+
+// ReplayEntry is a single request recovered from a prior run's output file. Query
+// is the statement as sent, with any plan variant and cache-busting nonce already
+// applied, and Parameters and TimeoutMs are the ones it was sent with.
+type ReplayEntry struct {
+	Query          string           `json:"query"`
+	QueryName      string           `json:"query_name"`
+	SequenceNumber int64            `json:"sequence_number"`
+	QueryVariant   int              `json:"query_variant"`
+	Parameters     *QueryParameters `json:"parameters"`
+	TimeoutMs      int64            `json:"timeout_ms"`
+}
+
+// ReplaySource hands out a prior run's requests in their original sequence order
+type ReplaySource struct {
+	entries []ReplayEntry
+	next    int64
+}
+
+// LoadReplaySource reads a prior run's output file and orders its requests by sequence number.
+// Records written before the query text was recorded fall back to fallbackQuery.
+func LoadReplaySource(path, fallbackQuery string) (*ReplaySource, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open replay file: %w", err)
+	}
+	defer file.Close()
+
+	var entries []ReplayEntry
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		// Array output has one element per line between the bracket lines
+		line := bytes.TrimSuffix(bytes.TrimSpace(scanner.Bytes()), []byte(","))
+		if len(line) == 0 || string(line) == "[" || string(line) == "]" {
+			continue
+		}
+
+		// Numbers stay as written so large integer parameters are sent back exactly
+		var entry ReplayEntry
+		decoder := json.NewDecoder(bytes.NewReader(line))
+		decoder.UseNumber()
+		if err := decoder.Decode(&entry); err != nil {
+			return nil, fmt.Errorf("invalid replay record on line %d: %w", lineNumber, err)
+		}
+		if entry.Query == "" {
+			entry.Query = fallbackQuery
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read replay file: %w", err)
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("replay file contains no records: %s", path)
+	}
+
+	// Records are written in completion order, so restore the dispatch order
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].SequenceNumber < entries[j].SequenceNumber
+	})
+
+	return &ReplaySource{entries: entries}, nil
+}
+
+// Next returns the next request to replay, or false once the sequence is exhausted
+func (s *ReplaySource) Next() (ReplayEntry, bool) {
+	index := atomic.AddInt64(&s.next, 1) - 1
+	if index >= int64(len(s.entries)) {
+		return ReplayEntry{}, false
+	}
+	return s.entries[index], true
+}
+
+// Len returns the number of requests in the replay sequence
+func (s *ReplaySource) Len() int {
+	return len(s.entries)
+}
@@ -0,0 +1,292 @@
+package benchmark
+
+import (
+	"bufio"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// reportTemplateHTML is the page layout. Charts are drawn as inline SVG, so the
+// report needs no scripts, stylesheets or fonts from anywhere else.
+//
+//go:embed report.html.tmpl
+var reportTemplateHTML string
+
+var reportTemplate = template.Must(template.New("report").Parse(reportTemplateHTML))
+
+// defaultReportBucketMs is the bucket size used for the report's time series when
+// BENCHMARK_AGGREGATE_BUCKET_MS is not set
+const defaultReportBucketMs = 1000
+
+// Chart geometry in SVG user units
+const (
+	chartWidth        = 800
+	chartHeight       = 300
+	chartMarginLeft   = 70
+	chartMarginRight  = 20
+	chartMarginTop    = 20
+	chartMarginBottom = 40
+	chartTicks        = 5
+)
+
+// aggregateBucketMs returns the time-bucket size in use: the configured one, or
+// the report's default when only the report asks for buckets
+func (r *SimpleAnalyticsRunner) aggregateBucketMs() int64 {
+	if r.config.AggregateBucketMs == 0 && r.config.GenerateReport {
+		return defaultReportBucketMs
+	}
+	return r.config.AggregateBucketMs
+}
+
+// reportPath returns where the HTML report for outputFile is written
+func reportPath(outputFile string) string {
+	return artifactBase(outputFile) + ".report.html"
+}
+
+// ReportGenerator renders a self-contained HTML report from a run's summary and
+// its time-bucket aggregates
+type ReportGenerator struct {
+	summaryPath string
+	bucketsPath string
+}
+
+// NewReportGenerator creates a generator for the run that wrote outputFile
+func NewReportGenerator(outputFile string) *ReportGenerator {
+	return &ReportGenerator{
+		summaryPath: summaryReportPath(outputFile),
+		bucketsPath: timeBucketReportPath(outputFile),
+	}
+}
+
+// Generate reads the run's data and writes the report to path
+func (g *ReportGenerator) Generate(path string) error {
+	data, err := os.ReadFile(g.summaryPath)
+	if err != nil {
+		return fmt.Errorf("failed to read summary: %w", err)
+	}
+	var summary SummaryReport
+	if err := json.Unmarshal(data, &summary); err != nil {
+		return fmt.Errorf("invalid summary %s: %w", g.summaryPath, err)
+	}
+	buckets, err := readTimeBuckets(g.bucketsPath)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return reportTemplate.Execute(file, newReportView(&summary, buckets))
+}
+
+// readTimeBuckets reads the aggregates written by TimeBucketAggregator
+func readTimeBuckets(path string) ([]TimeBucket, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read time buckets: %w", err)
+	}
+	defer file.Close()
+
+	var buckets []TimeBucket
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var bucket TimeBucket
+		if err := json.Unmarshal(scanner.Bytes(), &bucket); err != nil {
+			return nil, fmt.Errorf("invalid time bucket in %s: %w", path, err)
+		}
+		buckets = append(buckets, bucket)
+	}
+	return buckets, scanner.Err()
+}
+
+// reportView is the data the template renders
+type reportView struct {
+	Summary         *SummaryReport
+	StartTime       string
+	LatencyChart    lineChart
+	ThroughputChart lineChart
+	PercentileChart barChart
+}
+
+// lineChart is a time series chart with one polyline per series
+type lineChart struct {
+	Width, Height int
+	Series        []chartSeries
+	XAxis, YAxis  []axisLabel
+	Empty         bool
+}
+
+type chartSeries struct {
+	Name   string
+	Color  string
+	Points string
+}
+
+// barChart is a chart with one labelled bar per value
+type barChart struct {
+	Width, Height int
+	Bars          []chartBar
+	YAxis         []axisLabel
+}
+
+type chartBar struct {
+	Label               string
+	Value               string
+	X, Y, Width, Height float64
+	LabelX, LabelY      float64
+	AxisY               float64
+}
+
+// axisLabel is a tick label at a position along an axis
+type axisLabel struct {
+	Text string
+	X, Y float64
+}
+
+// newReportView lays out the charts for a run
+func newReportView(summary *SummaryReport, buckets []TimeBucket) reportView {
+	view := reportView{
+		Summary:   summary,
+		StartTime: time.UnixMilli(summary.StartTimeMs).UTC().Format(time.RFC3339),
+	}
+
+	latency := [][]float64{make([]float64, len(buckets)), make([]float64, len(buckets))}
+	throughput := [][]float64{make([]float64, len(buckets))}
+	times := make([]float64, len(buckets))
+	for i, bucket := range buckets {
+		times[i] = float64(bucket.BucketStartMs-buckets[0].BucketStartMs) / 1000.0
+		latency[0][i] = bucket.MeanLatencyMs
+		latency[1][i] = bucket.P99LatencyMs
+		throughput[0][i] = float64(bucket.Requests) * 1000.0 / float64(bucket.BucketMs)
+	}
+	view.LatencyChart = newLineChart(times, latency, []string{"mean", "p99"}, []string{"#1f77b4", "#d62728"}, "ms")
+	view.ThroughputChart = newLineChart(times, throughput, []string{"requests/s"}, []string{"#2ca02c"}, "")
+
+	p := summary.Latency
+	view.PercentileChart = newBarChart(
+		[]string{"p50", "p90", "p95", "p99", "max"},
+		[]float64{p.P50Ms, p.P90Ms, p.P95Ms, p.P99Ms, p.MaxMs})
+	return view
+}
+
+// newLineChart scales the series into the plot area, with seconds on the x axis
+func newLineChart(times []float64, series [][]float64, names, colors []string, unit string) lineChart {
+	chart := lineChart{Width: chartWidth, Height: chartHeight, Empty: len(times) < 2}
+	if chart.Empty {
+		return chart
+	}
+
+	maxX := times[len(times)-1]
+	maxY := 0.0
+	for _, values := range series {
+		for _, v := range values {
+			if v > maxY {
+				maxY = v
+			}
+		}
+	}
+	maxY = niceCeiling(maxY)
+
+	for s, values := range series {
+		points := make([]string, len(values))
+		for i, v := range values {
+			points[i] = formatPoint(chartX(times[i], maxX), chartY(v, maxY))
+		}
+		chart.Series = append(chart.Series, chartSeries{Name: names[s], Color: colors[s], Points: strings.Join(points, " ")})
+	}
+	for i := 0; i <= chartTicks; i++ {
+		x := maxX * float64(i) / chartTicks
+		y := maxY * float64(i) / chartTicks
+		chart.XAxis = append(chart.XAxis, axisLabel{Text: formatTick(x) + "s", X: chartX(x, maxX), Y: chartHeight - chartMarginBottom + 18})
+		chart.YAxis = append(chart.YAxis, axisLabel{Text: formatTick(y) + unit, X: chartMarginLeft - 8, Y: chartY(y, maxY) + 4})
+	}
+	return chart
+}
+
+// newBarChart lays out one bar per value
+func newBarChart(labels []string, values []float64) barChart {
+	chart := barChart{Width: chartWidth, Height: chartHeight}
+	maxY := 0.0
+	for _, v := range values {
+		if v > maxY {
+			maxY = v
+		}
+	}
+	maxY = niceCeiling(maxY)
+
+	plotWidth := float64(chartWidth - chartMarginLeft - chartMarginRight)
+	slot := plotWidth / float64(len(values))
+	for i, v := range values {
+		x := float64(chartMarginLeft) + slot*float64(i) + slot*0.2
+		y := chartY(v, maxY)
+		chart.Bars = append(chart.Bars, chartBar{
+			Label:  labels[i],
+			Value:  formatTick(v) + "ms",
+			X:      x,
+			Y:      y,
+			Width:  slot * 0.6,
+			Height: float64(chartHeight-chartMarginBottom) - y,
+			LabelX: x + slot*0.3,
+			LabelY: y - 6,
+			AxisY:  chartHeight - chartMarginBottom + 18,
+		})
+	}
+	for i := 0; i <= chartTicks; i++ {
+		y := maxY * float64(i) / chartTicks
+		chart.YAxis = append(chart.YAxis, axisLabel{Text: formatTick(y) + "ms", X: chartMarginLeft - 8, Y: chartY(y, maxY) + 4})
+	}
+	return chart
+}
+
+// chartX maps a value in [0, maxX] onto the plot area's width
+func chartX(x, maxX float64) float64 {
+	if maxX <= 0 {
+		return chartMarginLeft
+	}
+	return chartMarginLeft + x/maxX*float64(chartWidth-chartMarginLeft-chartMarginRight)
+}
+
+// chartY maps a value in [0, maxY] onto the plot area's height, growing upwards
+func chartY(y, maxY float64) float64 {
+	bottom := float64(chartHeight - chartMarginBottom)
+	return bottom - y/maxY*(bottom-chartMarginTop)
+}
+
+// niceCeiling rounds an axis maximum up to 1, 2 or 5 times a power of ten
+func niceCeiling(v float64) float64 {
+	if v <= 0 {
+		return 1
+	}
+	magnitude := 1.0
+	for magnitude*10 <= v {
+		magnitude *= 10
+	}
+	for magnitude > v {
+		magnitude /= 10
+	}
+	for _, step := range []float64{1, 2, 5, 10} {
+		if step*magnitude >= v {
+			return step * magnitude
+		}
+	}
+	return 10 * magnitude
+}
+
+func formatPoint(x, y float64) string {
+	return strconv.FormatFloat(x, 'f', 1, 64) + "," + strconv.FormatFloat(y, 'f', 1, 64)
+}
+
+func formatTick(v float64) string {
+	if v >= 100 || v == float64(int64(v)) {
+		return strconv.FormatFloat(v, 'f', 0, 64)
+	}
+	return strconv.FormatFloat(v, 'f', 2, 64)
+}
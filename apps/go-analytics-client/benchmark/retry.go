@@ -0,0 +1,96 @@
+package benchmark
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/couchbase/gocb/v2"
+	cbanalytics "github.com/couchbase/gocbanalytics"
+)
+
+// retryPredicates are the named transient-error checks BENCHMARK_RETRY_ON can select
+var retryPredicates = map[string][]error{
+	"timeout": {
+		context.DeadlineExceeded, gocb.ErrTimeout, gocb.ErrUnambiguousTimeout, gocb.ErrAmbiguousTimeout,
+		cbanalytics.ErrTimeout,
+	},
+	"temporary":   {gocb.ErrTemporaryFailure, gocb.ErrJobQueueFull, gocb.ErrOverload},
+	"unavailable": {gocb.ErrServiceNotAvailable, cbanalytics.ErrServiceUnavailable},
+}
+
+// nonRetryableErrors fail fast whatever the predicates say: retrying a query the
+// server cannot parse or compile only repeats the failure
+var nonRetryableErrors = []error{gocb.ErrParsingFailure, gocb.ErrCompilationFailure, gocb.ErrAuthenticationFailure,
+	cbanalytics.ErrInvalidCredential, cbanalytics.ErrInvalidArgument}
+
+// RetryPolicy retries requests that fail with a transient error, doubling the
+// backoff after every attempt up to MaxBackoff
+type RetryPolicy struct {
+	MaxRetries int
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+	retryOn    []error
+}
+
+// NewRetryPolicy builds the retry policy from the configuration
+func NewRetryPolicy(config Configuration) (RetryPolicy, error) {
+	policy := RetryPolicy{
+		MaxRetries: config.MaxRetries,
+		Backoff:    time.Duration(config.RetryBackoffMs) * time.Millisecond,
+		MaxBackoff: time.Duration(config.RetryMaxBackoffMs) * time.Millisecond,
+	}
+	if config.MaxRetries < 0 || config.RetryBackoffMs < 0 {
+		return policy, fmt.Errorf("BENCHMARK_MAX_RETRIES and BENCHMARK_RETRY_BACKOFF_MS must not be negative")
+	}
+	if config.RetryMaxBackoffMs < config.RetryBackoffMs {
+		return policy, fmt.Errorf("BENCHMARK_RETRY_MAX_BACKOFF_MS must not be below BENCHMARK_RETRY_BACKOFF_MS")
+	}
+
+	for _, name := range strings.Split(config.RetryOn, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		errs, ok := retryPredicates[name]
+		if !ok {
+			return policy, fmt.Errorf("unknown retry predicate: %s (expected timeout, temporary or unavailable)", name)
+		}
+		policy.retryOn = append(policy.retryOn, errs...)
+	}
+	return policy, nil
+}
+
+// Retryable reports whether err matches one of the policy's transient errors
+func (p RetryPolicy) Retryable(err error) bool {
+	for _, target := range nonRetryableErrors {
+		if errors.Is(err, target) {
+			return false
+		}
+	}
+	for _, target := range p.retryOn {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// Do calls attempt until it succeeds, fails with a non-retryable error, runs out
+// of retries or ctx is done, and returns the number of retries made and the last
+// error. Cancelling ctx also cuts a backoff short.
+func (p RetryPolicy) Do(ctx context.Context, attempt func() error) (int, error) {
+	backoff := p.Backoff
+	for retries := 0; ; retries++ {
+		err := attempt()
+		if err == nil || retries >= p.MaxRetries || !p.Retryable(err) || ctx.Err() != nil {
+			return retries, err
+		}
+		if !sleepContext(ctx, backoff) {
+			return retries, err
+		}
+		backoff = min(backoff*2, p.MaxBackoff)
+	}
+}
@@ -0,0 +1,37 @@
+package benchmark
+
+import "sync"
+
+// RollingStats collects the latencies completed since the last snapshot, so the
+// progress monitor can report percentiles over each interval rather than the run
+// so far. It is safe for concurrent use.
+type RollingStats struct {
+	mu      sync.Mutex
+	digits  int
+	current *LatencyRecorder
+}
+
+// NewRollingStats creates an empty window with the given histogram precision
+func NewRollingStats(significantDigits int) *RollingStats {
+	return &RollingStats{
+		digits:  significantDigits,
+		current: NewLatencyRecorder(significantDigits),
+	}
+}
+
+// Add records one latency in milliseconds in the current window
+func (s *RollingStats) Add(latencyMs float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.current.Record(latencyMs)
+}
+
+// Snapshot returns the latencies recorded since the previous snapshot and starts
+// a new window
+func (s *RollingStats) Snapshot() *LatencyRecorder {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	window := s.current
+	s.current = NewLatencyRecorder(s.digits)
+	return window
+}
@@ -0,0 +1,2101 @@
+package benchmark
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// Configuration holds all runner settings. Each field is read from its env variable,
+// which overrides the yaml key of an optional config file, which overrides the default.
+type Configuration struct {
+	DurationMs               int64  `env:"BENCHMARK_DURATION_MS" yaml:"duration_ms" required:"true"`
+	MaxRequests              int64  `env:"BENCHMARK_MAX_REQUESTS" yaml:"max_requests"`
+	CooldownMs               int64  `env:"BENCHMARK_COOLDOWN_MS" yaml:"cooldown_ms"`
+	WarmupMs                 int64  `env:"BENCHMARK_WARMUP_MS" yaml:"warmup_ms" required:"true"`
+	WarmupMode               string `env:"BENCHMARK_WARMUP_MODE" yaml:"warmup_mode" default:"fixed"`
+	WarmupCVThresholdPct     int    `env:"BENCHMARK_WARMUP_CV_THRESHOLD_PCT" yaml:"warmup_cv_threshold_pct" default:"5"`
+	WarmupWindowSize         int    `env:"BENCHMARK_WARMUP_WINDOW_SIZE" yaml:"warmup_window_size" default:"20"`
+	WarmupStableWindows      int    `env:"BENCHMARK_WARMUP_STABLE_WINDOWS" yaml:"warmup_stable_windows" default:"3"`
+	WarmupOutputFile         string `env:"BENCHMARK_WARMUP_OUTPUT_FILE" yaml:"warmup_output_file"`
+	RampUpMs                 int64  `env:"BENCHMARK_RAMP_UP_MS" yaml:"ramp_up_ms"`
+	Threads                  int    `env:"BENCHMARK_THREADS" yaml:"threads" required:"true"`
+	RequestIntervalMs        int64  `env:"BENCHMARK_REQUEST_INTERVAL_MS" yaml:"request_interval_ms"`
+	RequestJitterMs          int64  `env:"BENCHMARK_REQUEST_JITTER_MS" yaml:"request_jitter_ms"`
+	RandomSeed               int64  `env:"BENCHMARK_RANDOM_SEED" yaml:"random_seed"`
+	TargetRPS                int    `env:"BENCHMARK_TARGET_RPS" yaml:"target_rps"`
+	PerWorkerRPS             int    `env:"BENCHMARK_PER_WORKER_RPS" yaml:"per_worker_rps"`
+	ProgressReportIntervalMs int64  `env:"BENCHMARK_PROGRESS_INTERVAL_MS" yaml:"progress_interval_ms" required:"true"`
+	ProgressBar              bool   `env:"BENCHMARK_PROGRESS_BAR" yaml:"progress_bar"`
+	ThinkTimeMs              int64  `env:"BENCHMARK_THINK_TIME_MS" yaml:"think_time_ms"`
+	ThinkTimeDistribution    string `env:"BENCHMARK_THINK_TIME_DISTRIBUTION" yaml:"think_time_distribution" default:"fixed"`
+	ThinkTimeStages          string `env:"BENCHMARK_THINK_TIME_STAGES" yaml:"think_time_stages"`
+	MaxInFlight              int    `env:"BENCHMARK_MAX_IN_FLIGHT" yaml:"max_in_flight"`
+	PipelineDepth            int    `env:"BENCHMARK_PIPELINE_DEPTH" yaml:"pipeline_depth" default:"1"`
+	LoadModel                string `env:"BENCHMARK_LOAD_MODEL" yaml:"load_model" default:"closed"`
+	
+	ConnectionString   string `env:"CLUSTER_CONNECTION_STRING" yaml:"connection_string"`
+	Username           string `env:"CLUSTER_USERNAME" yaml:"username"`
+	Password           string `env:"CLUSTER_PASSWORD" yaml:"password"`
+	AnalyticsTimeoutS  int    `env:"BENCHMARK_ANALYTICS_TIMEOUT_S" yaml:"analytics_timeout_s" required:"true"`
+	ConnectionTimeoutS int    `env:"BENCHMARK_CONNECTION_TIMEOUT_S" yaml:"connection_timeout_s" required:"true"`
+	PerQueryTimeoutMs  int64  `env:"BENCHMARK_PER_QUERY_TIMEOUT_MS" yaml:"per_query_timeout_ms"`
+	NumConnections     int    `env:"BENCHMARK_NUM_CONNECTIONS" yaml:"num_connections" default:"1"`
+	AnalyticsPort      int    `env:"BENCHMARK_ANALYTICS_PORT" yaml:"analytics_port"`
+	
+	TLSCACertPath         string `env:"BENCHMARK_TLS_CA_CERT_PATH" yaml:"tls_ca_cert_path"`
+	TLSInsecureSkipVerify bool   `env:"BENCHMARK_TLS_INSECURE_SKIP_VERIFY" yaml:"tls_insecure_skip_verify"`
+	TLSClientCertPath     string `env:"BENCHMARK_TLS_CLIENT_CERT_PATH" yaml:"tls_client_cert_path"`
+	TLSClientKeyPath      string `env:"BENCHMARK_TLS_CLIENT_KEY_PATH" yaml:"tls_client_key_path"`
+	
+	HTTPIdleConnTimeoutMs   int64  `env:"BENCHMARK_HTTP_IDLE_CONN_TIMEOUT_MS" yaml:"http_idle_conn_timeout_ms" default:"-1"`
+	HTTPMaxIdleConnsPerHost int    `env:"BENCHMARK_HTTP_MAX_IDLE_CONNS_PER_HOST" yaml:"http_max_idle_conns_per_host"`
+	ExtraHeaders            string `env:"BENCHMARK_EXTRA_HEADERS" yaml:"extra_headers"`
+	
+	Query        string `env:"BENCHMARK_QUERY" yaml:"query"`
+	QueryFile    string `env:"BENCHMARK_QUERY_FILE" yaml:"query_file"`
+	QueryName    string `env:"BENCHMARK_QUERY_NAME" yaml:"query_name" required:"true"`
+	QueryParams  string `env:"BENCHMARK_QUERY_PARAMS" yaml:"query_params"`
+	
+	ExpectedMinRows int `env:"BENCHMARK_EXPECTED_MIN_ROWS" yaml:"expected_min_rows"`
+	ExpectedMaxRows int `env:"BENCHMARK_EXPECTED_MAX_ROWS" yaml:"expected_max_rows"`
+	
+	MaxRetries        int    `env:"BENCHMARK_MAX_RETRIES" yaml:"max_retries"`
+	RetryBackoffMs    int64  `env:"BENCHMARK_RETRY_BACKOFF_MS" yaml:"retry_backoff_ms" default:"100"`
+	RetryMaxBackoffMs int64  `env:"BENCHMARK_RETRY_MAX_BACKOFF_MS" yaml:"retry_max_backoff_ms" default:"5000"`
+	RetryOn           string `env:"BENCHMARK_RETRY_ON" yaml:"retry_on" default:"timeout,temporary,unavailable"`
+	
+	OutputFile     string `env:"BENCHMARK_OUTPUT_FILE" yaml:"output_file" required:"true"`
+	RunTimestamp   string `env:"BENCHMARK_RUN_TIMESTAMP" yaml:"run_timestamp" required:"true"`
+	SDKType        string `env:"BENCHMARK_SDK_TYPE" yaml:"sdk_type" required:"true"`
+	ReplayFile     string `env:"BENCHMARK_REPLAY_FILE" yaml:"replay_file"`
+	QueryLogFile   string `env:"BENCHMARK_QUERY_LOG_FILE" yaml:"query_log_file"`
+	OutputFormat   string `env:"BENCHMARK_OUTPUT_FORMAT" yaml:"output_format" default:"ndjson"`
+	CompressOutput bool   `env:"BENCHMARK_COMPRESS_OUTPUT" yaml:"compress_output"`
+	
+	QueryContextBucket string `env:"BENCHMARK_QUERY_CONTEXT_BUCKET" yaml:"query_context_bucket"`
+	QueryContextScope  string `env:"BENCHMARK_QUERY_CONTEXT_SCOPE" yaml:"query_context_scope"`
+	
+	WriterFlushEvery      int   `env:"BENCHMARK_WRITER_FLUSH_EVERY" yaml:"writer_flush_every" default:"1"`
+	WriterFlushIntervalMs int64 `env:"BENCHMARK_WRITER_FLUSH_INTERVAL_MS" yaml:"writer_flush_interval_ms"`
+	OutputTimeBucketMs    int64 `env:"BENCHMARK_OUTPUT_TIME_BUCKET_MS" yaml:"output_time_bucket_ms"`
+	WriterBufferSize      int   `env:"BENCHMARK_WRITER_BUFFER_SIZE" yaml:"writer_buffer_size" default:"1000"`
+	WriterSpillSize       int   `env:"BENCHMARK_WRITER_SPILL_SIZE" yaml:"writer_spill_size"`
+	
+	SLOs              string  `env:"BENCHMARK_SLOS" yaml:"slos"`
+	SLAMaxP99Ms       float64 `env:"BENCHMARK_SLA_MAX_P99_MS" yaml:"sla_max_p99_ms"`
+	SLAMinSuccessRate float64 `env:"BENCHMARK_SLA_MIN_SUCCESS_RATE" yaml:"sla_min_success_rate"`
+	
+	VerificationQuery      string `env:"BENCHMARK_VERIFICATION_QUERY" yaml:"verification_query"`
+	VerificationIntervalMs int64  `env:"BENCHMARK_VERIFICATION_INTERVAL_MS" yaml:"verification_interval_ms" default:"10000"`
+	VerificationOutputFile string `env:"BENCHMARK_VERIFICATION_OUTPUT_FILE" yaml:"verification_output_file"`
+	
+	EpochMs int64 `env:"BENCHMARK_EPOCH_MS" yaml:"epoch_ms"`
+	
+	StrictWorkers bool `env:"BENCHMARK_STRICT_WORKERS" yaml:"strict_workers"`
+	
+	PerWorkerStats bool `env:"BENCHMARK_PER_WORKER_STATS" yaml:"per_worker_stats"`
+	
+	OutlierTopN        int     `env:"BENCHMARK_OUTLIER_TOP_N" yaml:"outlier_top_n" default:"10"`
+	OutlierThresholdMs float64 `env:"BENCHMARK_OUTLIER_THRESHOLD_MS" yaml:"outlier_threshold_ms"`
+	
+	ResultFormat string `env:"BENCHMARK_RESULT_FORMAT" yaml:"result_format" default:"json"`
+	
+	WriterDegradeSampleEvery int   `env:"BENCHMARK_WRITER_DEGRADE_SAMPLE_EVERY" yaml:"writer_degrade_sample_every"`
+	WriterDegradeAfterMs     int64 `env:"BENCHMARK_WRITER_DEGRADE_AFTER_MS" yaml:"writer_degrade_after_ms" default:"5000"`
+	
+	SampleRate float64 `env:"BENCHMARK_SAMPLE_RATE" yaml:"sample_rate" default:"1"`
+	
+	CanaryTracing bool `env:"BENCHMARK_CANARY_TRACING" yaml:"canary_tracing"`
+	
+	AllocSampleEvery int `env:"BENCHMARK_ALLOC_SAMPLE_EVERY" yaml:"alloc_sample_every"`
+	
+	HistogramIntervalMs int64 `env:"BENCHMARK_HISTOGRAM_INTERVAL_MS" yaml:"histogram_interval_ms" default:"1000"`
+	AggregateBucketMs   int64 `env:"BENCHMARK_AGGREGATE_BUCKET_MS" yaml:"aggregate_bucket_ms"`
+	GenerateReport      bool  `env:"BENCHMARK_GENERATE_REPORT" yaml:"generate_report"`
+	
+	PushgatewayURL        string `env:"BENCHMARK_PUSHGATEWAY_URL" yaml:"pushgateway_url"`
+	PushgatewayIntervalMs int64  `env:"BENCHMARK_PUSHGATEWAY_INTERVAL_MS" yaml:"pushgateway_interval_ms" default:"5000"`
+	
+	MetricsHTTPPort int `env:"BENCHMARK_METRICS_HTTP_PORT" yaml:"metrics_http_port"`
+	
+	InfluxURL             string `env:"BENCHMARK_INFLUX_URL" yaml:"influx_url"`
+	InfluxOrg             string `env:"BENCHMARK_INFLUX_ORG" yaml:"influx_org"`
+	InfluxBucket          string `env:"BENCHMARK_INFLUX_BUCKET" yaml:"influx_bucket"`
+	InfluxToken           string `env:"BENCHMARK_INFLUX_TOKEN" yaml:"influx_token"`
+	InfluxBatchSize       int    `env:"BENCHMARK_INFLUX_BATCH_SIZE" yaml:"influx_batch_size" default:"500"`
+	InfluxFlushIntervalMs int64  `env:"BENCHMARK_INFLUX_FLUSH_INTERVAL_MS" yaml:"influx_flush_interval_ms" default:"1000"`
+	
+	OTelEndpoint        string `env:"BENCHMARK_OTEL_ENDPOINT" yaml:"otel_endpoint"`
+	OTelBatchSize       int    `env:"BENCHMARK_OTEL_BATCH_SIZE" yaml:"otel_batch_size" default:"512"`
+	OTelFlushIntervalMs int64  `env:"BENCHMARK_OTEL_FLUSH_INTERVAL_MS" yaml:"otel_flush_interval_ms" default:"5000"`
+	
+	HealthcheckPolicy     string `env:"BENCHMARK_HEALTHCHECK_POLICY" yaml:"healthcheck_policy" default:"fail"`
+	HealthcheckIntervalMs int64  `env:"BENCHMARK_HEALTHCHECK_INTERVAL_MS" yaml:"healthcheck_interval_ms"`
+	HealthcheckFailures   int    `env:"BENCHMARK_HEALTHCHECK_FAILURES" yaml:"healthcheck_failures" default:"3"`
+	KeepAliveIntervalMs   int64  `env:"BENCHMARK_KEEPALIVE_INTERVAL_MS" yaml:"keepalive_interval_ms"`
+	
+	LogLevel  string `env:"BENCHMARK_LOG_LEVEL" yaml:"log_level" default:"info"`
+	LogFormat string `env:"BENCHMARK_LOG_FORMAT" yaml:"log_format" default:"text"`
+	
+	DistinctQueries int  `env:"BENCHMARK_DISTINCT_QUERIES" yaml:"distinct_queries"`
+	CacheBusting    bool `env:"BENCHMARK_CACHE_BUSTING" yaml:"cache_busting"`
+	
+	StallTimeoutMs int64 `env:"BENCHMARK_STALL_TIMEOUT_MS" yaml:"stall_timeout_ms"`
+	
+	CheckpointIntervalMs int64 `env:"BENCHMARK_CHECKPOINT_INTERVAL_MS" yaml:"checkpoint_interval_ms"`
+	
+	SweepThreads            string `env:"BENCHMARK_SWEEP_THREADS" yaml:"sweep_threads"`
+	SweepRequestIntervalsMs string `env:"BENCHMARK_SWEEP_REQUEST_INTERVALS_MS" yaml:"sweep_request_intervals_ms"`
+	SweepPauseMs            int64  `env:"BENCHMARK_SWEEP_PAUSE_MS" yaml:"sweep_pause_ms" default:"5000"`
+	
+	QueryMixFile string `env:"BENCHMARK_QUERY_MIX_FILE" yaml:"query_mix_file"`
+	// Queries is an inline query mix, only settable from the config file
+	Queries []QueryMixEntry `yaml:"queries"`
+	// Clusters spreads the load over several weighted clusters instead of the
+	// connection string, only settable from the config file
+	Clusters []ClusterTarget `yaml:"clusters"`
+	
+	PercentilesIncludeFailures bool `env:"BENCHMARK_PERCENTILES_INCLUDE_FAILURES" yaml:"percentiles_include_failures"`
+	LatencySignificantDigits   int  `env:"BENCHMARK_LATENCY_SIGNIFICANT_DIGITS" yaml:"latency_significant_digits" default:"3"`
+	CorrectCoordinatedOmission bool `env:"BENCHMARK_CORRECT_COORDINATED_OMISSION" yaml:"correct_coordinated_omission"`
+	
+	SizeSampleEvery int  `env:"BENCHMARK_SIZE_SAMPLE_EVERY" yaml:"size_sample_every"`
+	ReconnectEvery  int  `env:"BENCHMARK_RECONNECT_EVERY" yaml:"reconnect_every"`
+	CapturePhases   bool `env:"BENCHMARK_CAPTURE_PHASES" yaml:"capture_phases"`
+	CountRowsOnly   bool `env:"BENCHMARK_COUNT_ROWS_ONLY" yaml:"count_rows_only"`
+	
+	ScanConsistency string `env:"BENCHMARK_SCAN_CONSISTENCY" yaml:"scan_consistency"`
+}
+
+// SimpleAnalyticsRunner is the main runner application
+type SimpleAnalyticsRunner struct {
+	config          Configuration
+	sequenceCounter int64
+	slos            []SLO
+	extraHeaders    map[string]string
+	thinkTimeStages []ThinkTimeStage
+	queryMix        *QueryMix
+	params          *ParamsTemplate
+	seed            int64
+}
+
+// LogConfiguration logs the settings the run will use
+func (r *SimpleAnalyticsRunner) LogConfiguration() {
+	log.Printf("📊 Configuration:")
+	log.Printf("   SDK Type: %s", r.config.SDKType)
+	log.Printf("   Duration: %dms", r.config.DurationMs)
+	if r.config.MaxRequests > 0 {
+		log.Printf("   Max Requests: %d (whichever of this and the duration comes first ends the test)", r.config.MaxRequests)
+	}
+	if r.config.PerQueryTimeoutMs > 0 {
+		log.Printf("   Per-Query Timeout: %dms (connections keep the %ds analytics timeout)",
+			r.config.PerQueryTimeoutMs, r.config.AnalyticsTimeoutS)
+	}
+	if r.config.ProgressBar && stderrIsTerminal() {
+		log.Printf("   Progress: terminal progress bar, redrawn every %dms", r.config.ProgressReportIntervalMs)
+	} else if r.config.ProgressBar {
+		log.Printf("   Progress: stderr is not a terminal, logging progress lines instead of the bar")
+	}
+	if r.config.CheckpointIntervalMs > 0 {
+		log.Printf("   Checkpoints: every %dms to %s", r.config.CheckpointIntervalMs, checkpointPath(r.config.OutputFile))
+	}
+	if r.config.CooldownMs > 0 {
+		log.Printf("   Cooldown: in-flight requests get up to %dms after the duration to complete", r.config.CooldownMs)
+	}
+	if r.config.WarmupMode == WarmupModeAdaptive {
+		log.Printf("   Warmup: up to %dms, until CV <= %d%% for %d windows of %d requests",
+			r.config.WarmupMs, r.config.WarmupCVThresholdPct, r.config.WarmupStableWindows, r.config.WarmupWindowSize)
+	} else {
+		log.Printf("   Warmup: %dms", r.config.WarmupMs)
+	}
+	log.Printf("   Threads: %d", r.config.Threads)
+	if r.config.PipelineDepth > 1 {
+		log.Printf("   Pipeline Depth: %d in-flight requests per worker", r.config.PipelineDepth)
+	}
+	if r.config.RampUpMs > 0 {
+		log.Printf("   Ramp-Up: %dms", r.config.RampUpMs)
+	}
+	log.Printf("   Load Model: %s", r.config.LoadModel)
+	log.Printf("   Random Seed: %d", r.seed)
+	if r.config.TargetRPS > 0 {
+		log.Printf("   Target Throughput: %d RPS across all workers", r.config.TargetRPS)
+	} else if r.config.PerWorkerRPS > 0 {
+		log.Printf("   Per-Worker Rate: %d RPS per worker (%d RPS across all workers)",
+			r.config.PerWorkerRPS, r.config.PerWorkerRPS*r.config.Threads)
+	} else {
+		log.Printf("   Request Interval: %dms per worker", r.config.RequestIntervalMs)
+		if r.config.RequestJitterMs > 0 {
+			log.Printf("   Request Jitter: ±%dms", r.config.RequestJitterMs)
+		}
+	}
+	if r.config.ThinkTimeMs > 0 {
+		log.Printf("   Think Time: %dms (%s)", r.config.ThinkTimeMs, r.config.ThinkTimeDistribution)
+	}
+	for i, stage := range r.thinkTimeStages {
+		log.Printf("   Think Time Stage %d: %v, %dms (%s)", i+1, stage.Duration, stage.MeanMs, stage.Distribution)
+	}
+	if r.config.MaxInFlight > 0 {
+		log.Printf("   Max In-Flight: %d", r.config.MaxInFlight)
+	}
+	if r.config.ReconnectEvery > 0 {
+		log.Printf("   Reconnect Every: %d requests per worker", r.config.ReconnectEvery)
+	}
+	if entries := r.queryMix.Entries(); len(entries) > 1 {
+		for _, entry := range entries {
+			timeout := "global timeout"
+			if entry.TimeoutMs > 0 {
+				timeout = fmt.Sprintf("%dms timeout", entry.TimeoutMs)
+			}
+			log.Printf("   Query Mix: %s (weight %g, %s): %s", entry.Name, entry.Weight, timeout, entry.Query)
+		}
+	} else {
+		if r.config.QueryFile != "" {
+			log.Printf("   Query File: %s", r.config.QueryFile)
+		}
+		log.Printf("   Query: %s", r.config.Query)
+	}
+	if r.config.CompressOutput {
+		log.Printf("   Output: %s (%s, gzip)", r.config.OutputFile, r.config.OutputFormat)
+	} else {
+		log.Printf("   Output: %s (%s)", r.config.OutputFile, r.config.OutputFormat)
+	}
+	if r.config.CountRowsOnly {
+		log.Printf("   Row Handling: count only, rows are not decoded")
+	}
+	log.Printf("   Scan Consistency: %s", r.scanConsistency())
+	if r.config.SampleRate < 1 {
+		log.Printf("   Result Sampling: %g%% of results written; the summary covers every request", r.config.SampleRate*100)
+	}
+	if r.config.ReplayFile != "" {
+		log.Printf("   Replaying: %s", r.config.ReplayFile)
+	}
+	if r.config.QueryLogFile != "" {
+		log.Printf("   Query Log: %s", r.config.QueryLogFile)
+	}
+	if r.config.ExpectedMinRows > 0 || r.config.ExpectedMaxRows > 0 {
+		log.Printf("   Expected Rows: min %d, max %d (0 = unchecked)", r.config.ExpectedMinRows, r.config.ExpectedMaxRows)
+	}
+	if r.config.QueryContextBucket != "" {
+		log.Printf("   Query Context: %s.%s", r.config.QueryContextBucket, r.config.QueryContextScope)
+	}
+	if r.config.DistinctQueries > 0 {
+		log.Printf("   Distinct Query Variants: %d (plan-cache pressure mode)", r.config.DistinctQueries)
+	}
+	if r.config.CacheBusting {
+		log.Printf("   Cache Busting: every request carries a unique nonce comment")
+	}
+	if r.config.VerificationQuery != "" {
+		log.Printf("   Verification Query: %s (every %dms)", r.config.VerificationQuery, r.config.VerificationIntervalMs)
+	}
+	log.Printf("   Run Timestamp: %s", r.config.RunTimestamp)
+	if r.config.EpochMs > 0 {
+		log.Printf("   Shared Epoch: %d (offset %dms from local clock)",
+			r.config.EpochMs, time.Now().UnixMilli()-r.config.EpochMs)
+	}
+	logConnectionPoolSettings(r.config)
+	if len(r.extraHeaders) > 0 {
+		log.Printf("   Extra Headers: %s", RedactedHeaders(r.extraHeaders))
+		// Neither gocb nor gocbanalytics exposes a hook for per-request HTTP headers
+		logWarnf("The %s SDK handler cannot attach custom HTTP headers; extra headers are recorded but NOT sent",
+			r.config.SDKType)
+	}
+	
+	warnOnSchedulingPressure(r.config.Threads * r.config.PipelineDepth)
+}
+
+// NewSimpleAnalyticsRunner validates config and creates a runner for it. The
+// configuration can come from LoadConfiguration or be built directly, starting
+// from DefaultConfiguration. Logging is left to the caller; see SetupLogging.
+func NewSimpleAnalyticsRunner(config Configuration) (*SimpleAnalyticsRunner, error) {
+	if err := validateRequired(config); err != nil {
+		return nil, err
+	}
+	if err := resolveQuery(&config); err != nil {
+		return nil, err
+	}
+	
+	if err := validateThinkTimeDistribution(config.ThinkTimeDistribution); err != nil {
+		return nil, err
+	}
+	if err := validateSDKType(config.SDKType); err != nil {
+		return nil, err
+	}
+	if err := validateLoadModel(config.LoadModel); err != nil {
+		return nil, err
+	}
+	if err := validateWarmupMode(config.WarmupMode); err != nil {
+		return nil, err
+	}
+	if err := validateSignificantDigits(config.LatencySignificantDigits); err != nil {
+		return nil, err
+	}
+	if err := validateConnections(config); err != nil {
+		return nil, err
+	}
+	if err := validateAuth(config); err != nil {
+		return nil, err
+	}
+	if err := validateQueryContext(config); err != nil {
+		return nil, err
+	}
+	if config.NumConnections < 1 {
+		return nil, fmt.Errorf("BENCHMARK_NUM_CONNECTIONS must be at least 1, got %d", config.NumConnections)
+	}
+	if config.PipelineDepth < 1 {
+		return nil, fmt.Errorf("BENCHMARK_PIPELINE_DEPTH must be at least 1, got %d", config.PipelineDepth)
+	}
+	// A recycled connection belongs to one worker and serves one request at a time
+	if config.PipelineDepth > 1 && config.ReconnectEvery > 0 {
+		return nil, fmt.Errorf("BENCHMARK_PIPELINE_DEPTH cannot be combined with BENCHMARK_RECONNECT_EVERY")
+	}
+	// Correction needs a fixed per-worker schedule to know which requests were held back
+	if config.CorrectCoordinatedOmission && (config.RequestIntervalMs <= 0 || config.TargetRPS > 0 ||
+		config.ThinkTimeMs > 0 || config.ThinkTimeStages != "" || config.LoadModel == LoadModelOpen) {
+		return nil, fmt.Errorf("BENCHMARK_CORRECT_COORDINATED_OMISSION needs closed-loop BENCHMARK_REQUEST_INTERVAL_MS pacing " +
+			"without think time or BENCHMARK_TARGET_RPS; the open model reports dispatch lag instead")
+	}
+	if config.MaxRequests < 0 {
+		return nil, fmt.Errorf("BENCHMARK_MAX_REQUESTS must not be negative")
+	}
+	if config.PerQueryTimeoutMs < 0 || config.PerQueryTimeoutMs > int64(config.AnalyticsTimeoutS)*1000 {
+		return nil, fmt.Errorf("BENCHMARK_PER_QUERY_TIMEOUT_MS must be between 0 and BENCHMARK_ANALYTICS_TIMEOUT_S, got %dms", config.PerQueryTimeoutMs)
+	}
+	if config.CooldownMs < 0 {
+		return nil, fmt.Errorf("BENCHMARK_COOLDOWN_MS must not be negative")
+	}
+	if config.CheckpointIntervalMs < 0 {
+		return nil, fmt.Errorf("BENCHMARK_CHECKPOINT_INTERVAL_MS must not be negative")
+	}
+	if isStdoutOutput(config.WarmupOutputFile) {
+		return nil, fmt.Errorf("BENCHMARK_WARMUP_OUTPUT_FILE must be a file; stdout is reserved for the measured results")
+	}
+	if config.QueryLogFile != "" && (config.ReplayFile != "" || config.LoadModel == LoadModelOpen) {
+		return nil, fmt.Errorf("BENCHMARK_QUERY_LOG_FILE cannot be combined with BENCHMARK_REPLAY_FILE or the open load model")
+	}
+	if config.RampUpMs < 0 || config.RampUpMs > config.DurationMs {
+		return nil, fmt.Errorf("BENCHMARK_RAMP_UP_MS must be between 0 and BENCHMARK_DURATION_MS")
+	}
+	if config.WarmupMode == WarmupModeAdaptive &&
+		(config.WarmupCVThresholdPct <= 0 || config.WarmupWindowSize < 2 || config.WarmupStableWindows < 1) {
+		return nil, fmt.Errorf("adaptive warmup needs a positive CV threshold, a window size of at least 2 and at least 1 stable window")
+	}
+	if err := validatePacing(config); err != nil {
+		return nil, err
+	}
+	if err := validateOutputFormat(config.OutputFormat); err != nil {
+		return nil, err
+	}
+	if config.OutputFormat == OutputFormatHistogram && config.HistogramIntervalMs <= 0 {
+		return nil, fmt.Errorf("BENCHMARK_HISTOGRAM_INTERVAL_MS must be positive, got %d", config.HistogramIntervalMs)
+	}
+	if config.OutputTimeBucketMs > 0 && config.OutputFormat != OutputFormatNDJSON {
+		return nil, fmt.Errorf("BENCHMARK_OUTPUT_TIME_BUCKET_MS requires the %s output format", OutputFormatNDJSON)
+	}
+	if strings.HasSuffix(config.OutputFile, ".gz") {
+		config.CompressOutput = true
+	}
+	if config.CompressOutput && (isStdoutOutput(config.OutputFile) ||
+		(config.OutputFormat != OutputFormatNDJSON && config.OutputFormat != OutputFormatArray)) {
+		return nil, fmt.Errorf("BENCHMARK_COMPRESS_OUTPUT requires an output file and the %s or %s output format", OutputFormatNDJSON, OutputFormatArray)
+	}
+	if config.WriterBufferSize < 1 || config.WriterSpillSize < 0 {
+		return nil, fmt.Errorf("BENCHMARK_WRITER_BUFFER_SIZE must be positive and BENCHMARK_WRITER_SPILL_SIZE must not be negative")
+	}
+	if config.ExpectedMinRows < 0 || config.ExpectedMaxRows < 0 ||
+		(config.ExpectedMaxRows > 0 && config.ExpectedMaxRows < config.ExpectedMinRows) {
+		return nil, fmt.Errorf("BENCHMARK_EXPECTED_MIN_ROWS and BENCHMARK_EXPECTED_MAX_ROWS must not be negative, and the maximum must not be below the minimum")
+	}
+	if config.OutlierTopN < 0 || config.OutlierThresholdMs < 0 {
+		return nil, fmt.Errorf("BENCHMARK_OUTLIER_TOP_N and BENCHMARK_OUTLIER_THRESHOLD_MS must not be negative")
+	}
+	if config.SampleRate <= 0 || config.SampleRate > 1 {
+		return nil, fmt.Errorf("BENCHMARK_SAMPLE_RATE must be above 0 and at most 1, got %g", config.SampleRate)
+	}
+	if config.MetricsHTTPPort < 0 || config.MetricsHTTPPort > 65535 {
+		return nil, fmt.Errorf("BENCHMARK_METRICS_HTTP_PORT must be a valid port, got %d", config.MetricsHTTPPort)
+	}
+	if config.AggregateBucketMs < 0 {
+		return nil, fmt.Errorf("BENCHMARK_AGGREGATE_BUCKET_MS must not be negative")
+	}
+	if isStdoutOutput(config.OutputFile) {
+		if config.OutputFormat != OutputFormatNDJSON && config.OutputFormat != OutputFormatArray {
+			return nil, fmt.Errorf("streaming to stdout requires the %s or %s output format", OutputFormatNDJSON, OutputFormatArray)
+		}
+		if config.OutputTimeBucketMs > 0 {
+			return nil, fmt.Errorf("BENCHMARK_OUTPUT_TIME_BUCKET_MS cannot be used when streaming to stdout")
+		}
+		if config.SDKType == SDKTypeBoth {
+			return nil, fmt.Errorf("the %s SDK type writes one output file per SDK and cannot stream to stdout", SDKTypeBoth)
+		}
+	}
+	if err := validateResultFormat(config.ResultFormat); err != nil {
+		return nil, err
+	}
+	if _, err := NewRetryPolicy(config); err != nil {
+		return nil, err
+	}
+	if config.PushgatewayURL != "" && config.PushgatewayIntervalMs <= 0 {
+		return nil, fmt.Errorf("BENCHMARK_PUSHGATEWAY_INTERVAL_MS must be positive")
+	}
+	if config.InfluxURL != "" {
+		if config.InfluxOrg == "" || config.InfluxBucket == "" || config.InfluxToken == "" {
+			return nil, fmt.Errorf("BENCHMARK_INFLUX_URL requires BENCHMARK_INFLUX_ORG, BENCHMARK_INFLUX_BUCKET and BENCHMARK_INFLUX_TOKEN")
+		}
+		if config.InfluxBatchSize <= 0 || config.InfluxFlushIntervalMs <= 0 {
+			return nil, fmt.Errorf("BENCHMARK_INFLUX_BATCH_SIZE and BENCHMARK_INFLUX_FLUSH_INTERVAL_MS must be positive")
+		}
+	}
+	if config.OTelEndpoint != "" && (config.OTelBatchSize <= 0 || config.OTelFlushIntervalMs <= 0) {
+		return nil, fmt.Errorf("BENCHMARK_OTEL_BATCH_SIZE and BENCHMARK_OTEL_FLUSH_INTERVAL_MS must be positive")
+	}
+	if err := validateHealthcheckPolicy(config.HealthcheckPolicy); err != nil {
+		return nil, err
+	}
+	if err := validateScanConsistency(config.ScanConsistency); err != nil {
+		return nil, err
+	}
+	if config.HealthcheckIntervalMs < 0 || config.HealthcheckFailures < 1 {
+		return nil, fmt.Errorf("BENCHMARK_HEALTHCHECK_INTERVAL_MS must not be negative and BENCHMARK_HEALTHCHECK_FAILURES must be at least 1")
+	}
+	if config.KeepAliveIntervalMs < 0 {
+		return nil, fmt.Errorf("BENCHMARK_KEEPALIVE_INTERVAL_MS must not be negative")
+	}
+	// Connection churn measures fresh connections, which keepalives would defeat
+	if config.KeepAliveIntervalMs > 0 && config.ReconnectEvery > 0 {
+		return nil, fmt.Errorf("BENCHMARK_KEEPALIVE_INTERVAL_MS cannot be combined with BENCHMARK_RECONNECT_EVERY")
+	}
+	if config.VerificationQuery != "" && config.VerificationIntervalMs <= 0 {
+		return nil, fmt.Errorf("BENCHMARK_VERIFICATION_INTERVAL_MS must be positive, got %d", config.VerificationIntervalMs)
+	}
+	if config.VerificationQuery != "" && config.VerificationOutputFile == "" {
+		config.VerificationOutputFile = artifactBase(config.OutputFile) + ".verification.jsonl"
+	}
+	slos, err := ParseSLOs(config.SLOs)
+	if err != nil {
+		return nil, err
+	}
+	if config.SLAMaxP99Ms < 0 || config.SLAMinSuccessRate < 0 || config.SLAMinSuccessRate > 100 {
+		return nil, fmt.Errorf("BENCHMARK_SLA_MAX_P99_MS must not be negative and BENCHMARK_SLA_MIN_SUCCESS_RATE must be between 0 and 100")
+	}
+	// The p99 SLA is gated like any other latency SLO
+	if config.SLAMaxP99Ms > 0 {
+		slos = append(slos, SLO{Name: "p99", Percentile: 99, Threshold: time.Duration(config.SLAMaxP99Ms * float64(time.Millisecond))})
+	}
+	extraHeaders, err := ParseExtraHeaders(config.ExtraHeaders)
+	if err != nil {
+		return nil, err
+	}
+	thinkTimeStages, err := ParseThinkTimeStages(config.ThinkTimeStages)
+	if err != nil {
+		return nil, err
+	}
+	// A single configured query runs as a one-entry mix
+	var queryMix *QueryMix
+	switch {
+	case config.QueryMixFile != "" && len(config.Queries) > 0:
+		return nil, fmt.Errorf("set either BENCHMARK_QUERY_MIX_FILE or an inline queries list, not both")
+	case config.QueryMixFile != "":
+		queryMix, err = LoadQueryMix(config.QueryMixFile)
+	case len(config.Queries) > 0:
+		queryMix, err = NewQueryMix(config.Queries)
+	default:
+		queryMix, err = NewQueryMix([]QueryMixEntry{{Name: config.QueryName, Query: config.Query, Weight: 1}})
+	}
+	if err != nil {
+		return nil, err
+	}
+	params, err := ParseParamsTemplate(config.QueryParams)
+	if err != nil {
+		return nil, err
+	}
+	
+	// Without a configured seed every run is seeded from the clock; the seed is
+	// logged and recorded in the summary so the run can be reproduced
+	seed := config.RandomSeed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	
+	return &SimpleAnalyticsRunner{
+		config:          config,
+		sequenceCounter: 0,
+		slos:            slos,
+		extraHeaders:    extraHeaders,
+		thinkTimeStages: thinkTimeStages,
+		queryMix:        queryMix,
+		params:          params,
+		seed:            seed,
+	}, nil
+}
+
+// Run executes the performance test until it completes or ctx is cancelled, which
+// stops the workers and flushes the results like the end of the duration does.
+// A comparison run writes one summary per SDK and returns no report.
+func (r *SimpleAnalyticsRunner) Run(ctx context.Context) (*SummaryReport, error) {
+	if r.config.SDKType == SDKTypeBoth {
+		return nil, r.runComparison(ctx)
+	}
+	return r.runSDK(ctx)
+}
+
+// Run validates config, logs it and runs the benchmark, returning its summary.
+// It is the entry point for running benchmarks from other Go programs.
+func Run(ctx context.Context, config Configuration) (*SummaryReport, error) {
+	runner, err := NewSimpleAnalyticsRunner(config)
+	if err != nil {
+		return nil, err
+	}
+	runner.LogConfiguration()
+	return runner.Run(ctx)
+}
+
+// runSDK connects with the configured SDK, warms up and runs the measurement
+func (r *SimpleAnalyticsRunner) runSDK(ctx context.Context) (*SummaryReport, error) {
+	// Self-test: confirm the SDK library is linked before connecting with it
+	version, err := sdkLibraryVersion(r.config.SDKType)
+	if err != nil {
+		logWarnf("SDK self-test: %v", err)
+	} else {
+		log.Printf("SDK self-test: %s SDK linked (%s)", r.config.SDKType, version)
+	}
+	
+	// Create SDK handler; the constructors verify connectivity with a probe query
+	handler, err := r.createSDKHandler()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s SDK handler (check CLUSTER_CONNECTION_STRING and credentials): %w",
+			r.config.SDKType, err)
+	}
+	defer handler.Close()
+	log.Printf("✅ SDK self-test passed: %s handler constructed and connected", handler.GetSDKType())
+	
+	// Run warmup
+	if err := r.runWarmup(ctx, handler); err != nil {
+		return nil, fmt.Errorf("warmup failed: %w", err)
+	}
+	if ctx.Err() != nil {
+		return nil, fmt.Errorf("interrupted during warmup")
+	}
+	
+	// Run performance test
+	report, err := r.runPerformanceTest(ctx, handler)
+	if err != nil {
+		return report, fmt.Errorf("performance test failed: %w", err)
+	}
+	return report, nil
+}
+
+// scanConsistency names the scan consistency requests run with
+func (r *SimpleAnalyticsRunner) scanConsistency() string {
+	if r.config.ScanConsistency == "" {
+		return ScanConsistencySDKDefault
+	}
+	return r.config.ScanConsistency
+}
+
+// createSDKHandler creates appropriate SDK handler based on configuration, with
+// one handler per cluster when several clusters are configured
+func (r *SimpleAnalyticsRunner) createSDKHandler() (AnalyticsSDKHandler, error) {
+	if len(r.config.Clusters) > 0 {
+		return NewMultiClusterHandler(r.config.Clusters, func(connectionString string) (AnalyticsSDKHandler, error) {
+			config := r.config
+			config.ConnectionString = connectionString
+			return newSDKHandler(config)
+		})
+	}
+	return newSDKHandler(r.config)
+}
+
+// newSDKHandler creates the handler for the configured SDK type
+func newSDKHandler(config Configuration) (AnalyticsSDKHandler, error) {
+	switch config.SDKType {
+	case SDKTypeOperational:
+		return NewOperationalSDKHandler(config)
+	case SDKTypeEnterprise:
+		return NewEnterpriseSDKHandler(config)
+	default:
+		return nil, validateSDKType(config.SDKType)
+	}
+}
+
+// createMetricsWriter creates the output sink for the configured output format
+func (r *SimpleAnalyticsRunner) createMetricsWriter() MetricsWriter {
+	switch r.config.OutputFormat {
+	case OutputFormatSQLite:
+		return NewMetricsSQLiteWriter(r.config.OutputFile, r.config.WriterBufferSize)
+	case OutputFormatCSV:
+		return NewMetricsCSVWriter(r.config.OutputFile, r.config.WriterBufferSize)
+	case OutputFormatHistogram:
+		return NewMetricsHistogramWriter(r.config.OutputFile, time.Duration(r.config.HistogramIntervalMs)*time.Millisecond,
+			r.config.LatencySignificantDigits)
+	default:
+		return NewMetricsJSONWriter(r.config.OutputFile, r.config.WriterBufferSize, r.config.WriterFlushEvery,
+			time.Duration(r.config.WriterFlushIntervalMs)*time.Millisecond, r.config.OutputTimeBucketMs,
+			r.config.OutputFormat == OutputFormatArray, r.config.CompressOutput)
+	}
+}
+
+// runWarmup performs JIT warmup. Adaptive warmup ends early once latencies have
+// stabilized, with WarmupMs as the upper bound.
+func (r *SimpleAnalyticsRunner) runWarmup(parent context.Context, handler AnalyticsSDKHandler) error {
+	log.Printf("🔥 Starting %s warmup for up to %dms...", r.config.WarmupMode, r.config.WarmupMs)
+	
+	ctx, cancel := context.WithTimeout(parent, time.Duration(r.config.WarmupMs)*time.Millisecond)
+	defer cancel()
+	
+	var stabilizer *WarmupStabilizer
+	if r.config.WarmupMode == WarmupModeAdaptive {
+		stabilizer = NewWarmupStabilizer(r.config.WarmupWindowSize,
+			float64(r.config.WarmupCVThresholdPct), r.config.WarmupStableWindows)
+	}
+	var stabilized int32
+	warmupStart := time.Now()
+	
+	// Warmup results are only kept for diagnosis, in their own file and out of the summary
+	var writer MetricsWriter
+	if r.config.WarmupOutputFile != "" {
+		writer = NewMetricsJSONWriter(r.config.WarmupOutputFile, r.config.WriterBufferSize, r.config.WriterFlushEvery,
+			time.Duration(r.config.WriterFlushIntervalMs)*time.Millisecond, 0, false, false)
+		writerCtx, writerCancel := context.WithCancel(context.Background())
+		go writer.Start(writerCtx)
+		defer func() {
+			writerCancel()
+			writer.Wait()
+			log.Printf("   Warmup results written: %d to %s", writer.GetWrittenCount(), r.config.WarmupOutputFile)
+		}()
+	}
+	
+	var wg sync.WaitGroup
+	for i := 0; i < r.config.Threads; i++ {
+		wg.Add(1)
+		go func(workerID int) {
+			defer wg.Done()
+			rng := r.rngFor("warmup", workerID)
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				default:
+					seq := atomic.AddInt64(&r.sequenceCounter, 1)
+					result := handler.ExecuteQuery(ctx, r.config.Query, "warmup", seq, r.queryTimeout(), r.renderParams(seq, rng))
+					// Requests cut off by the end of the warmup are not worth keeping. The
+					// warmup deadline surfaces as a timeout, not a cancellation, so a request
+					// still running when the warmup context ended is dropped either way.
+					if writer != nil && result.ErrorCategory != ErrorCategoryCanceled && ctx.Err() == nil {
+						writer.WriteResult(result)
+					}
+					// Suppress warmup errors
+					if stabilizer != nil && result.Success && stabilizer.Add(result.DurationMs) {
+						atomic.StoreInt32(&stabilized, 1)
+						cancel()
+					}
+				}
+			}
+		}(i)
+	}
+	
+	wg.Wait()
+	if stabilizer != nil {
+		windows, cvPct := stabilizer.Snapshot()
+		if atomic.LoadInt32(&stabilized) == 1 {
+			log.Printf("✅ Warmup stabilized after %v (%d windows, last CV %.1f%%)",
+				time.Since(warmupStart).Round(time.Millisecond), windows, cvPct)
+			return nil
+		}
+		logWarnf("Warmup did not stabilize within %dms (%d windows, last CV %.1f%%)", r.config.WarmupMs, windows, cvPct)
+	}
+	log.Println("✅ Warmup complete")
+	return nil
+}
+
+// runPerformanceTest executes the main performance test
+func (r *SimpleAnalyticsRunner) runPerformanceTest(ctx context.Context, handler AnalyticsSDKHandler) (*SummaryReport, error) {
+	log.Printf("📊 Starting performance measurement for %dms", r.config.DurationMs)
+	
+	var requestCount, successCount int64
+	// completedCount counts requests that came back, failed or not; requestCount
+	// already includes those still in flight
+	var completedCount int64
+	var busyNanos int64
+	var semaphoreWaitNanos int64
+	sizeStats := &SizeLatencyStats{}
+	schedulingStats := &SchedulingLatencyStats{}
+	reconnectStats := &ReconnectStats{}
+	phaseStats := NewPhaseStats()
+	
+	// Optional cap on concurrently executing queries across all workers
+	var inFlight chan struct{}
+	if r.config.MaxInFlight > 0 {
+		inFlight = make(chan struct{}, r.config.MaxInFlight)
+	}
+	
+	// ✅ FIXED: Reset sequence counter for actual test (separate from warmup)
+	atomic.StoreInt64(&r.sequenceCounter, 0)
+	
+	// Replay mode re-issues a prior run's requests instead of the configured query
+	var replay *ReplaySource
+	if r.config.ReplayFile != "" {
+		var err error
+		replay, err = LoadReplaySource(r.config.ReplayFile, r.config.Query)
+		if err != nil {
+			return nil, err
+		}
+		log.Printf("🔁 Replaying %d requests from %s", replay.Len(), r.config.ReplayFile)
+	}
+	
+	// Query-log mode streams a recorded workload instead of the configured query
+	var queryLog *QueryLogSource
+	if r.config.QueryLogFile != "" {
+		var err error
+		queryLog, err = OpenQueryLogSource(r.config.QueryLogFile)
+		if err != nil {
+			return nil, err
+		}
+		log.Printf("📜 Streaming queries from %s", r.config.QueryLogFile)
+	}
+	
+	// Create metrics writer
+	writer := r.createMetricsWriter()
+	queueCapacity := r.config.WriterBufferSize
+	var spilling *SpillingMetricsWriter
+	if r.config.WriterSpillSize > 0 {
+		spilling = NewSpillingMetricsWriter(writer, r.config.WriterBufferSize, r.config.WriterSpillSize)
+		writer = spilling
+		queueCapacity += r.config.WriterSpillSize
+	}
+	var degrading *DegradingMetricsWriter
+	if r.config.WriterDegradeSampleEvery > 1 {
+		degrading = NewDegradingMetricsWriter(writer, queueCapacity, r.config.WriterDegradeSampleEvery,
+			time.Duration(r.config.WriterDegradeAfterMs)*time.Millisecond)
+		writer = degrading
+	}
+	// Sampled-out results are skipped before they reach any queue
+	var sampling *SamplingMetricsWriter
+	if r.config.SampleRate < 1 {
+		sampling = NewSamplingMetricsWriter(writer, r.config.SampleRate, r.rngFor("sample", 0))
+		writer = sampling
+	}
+	// Live sinks see every result, even while the file output is sampling
+	var live []MetricsWriter
+	if r.config.PushgatewayURL != "" {
+		live = append(live, NewPrometheusWriter(r.config.PushgatewayURL, r.config.RunTimestamp,
+			time.Duration(r.config.PushgatewayIntervalMs)*time.Millisecond))
+	}
+	if r.config.InfluxURL != "" {
+		live = append(live, NewInfluxWriter(r.config.InfluxURL, r.config.InfluxOrg, r.config.InfluxBucket, r.config.InfluxToken,
+			r.config.InfluxBatchSize, time.Duration(r.config.InfluxFlushIntervalMs)*time.Millisecond))
+	}
+	if r.config.OTelEndpoint != "" {
+		otelWriter, err := NewOTelWriter(r.config.OTelEndpoint, r.config.RunTimestamp,
+			r.config.OTelBatchSize, time.Duration(r.config.OTelFlushIntervalMs)*time.Millisecond)
+		if err != nil {
+			return nil, err
+		}
+		live = append(live, otelWriter)
+	}
+	if bucketMs := r.aggregateBucketMs(); bucketMs > 0 {
+		// A bucket can only be written once every request that started in it has
+		// finished, including retries
+		lateness := r.queryMix.MaxTimeout(r.queryTimeout()) * time.Duration(r.config.MaxRetries+1)
+		live = append(live, NewTimeBucketAggregator(timeBucketReportPath(r.config.OutputFile), bucketMs, lateness,
+			r.config.LatencySignificantDigits))
+	}
+	if len(live) > 0 {
+		writer = NewTeeMetricsWriter(writer, live...)
+	}
+	writerCtx, writerCancel := context.WithCancel(context.Background())
+	
+	go writer.Start(writerCtx)
+	
+	// Periodic correctness check, kept off the worker pool and out of the metrics
+	var verifier *Verifier
+	if r.config.VerificationQuery != "" {
+		verifier = NewVerifier(handler, r.config.VerificationQuery,
+			time.Duration(r.config.VerificationIntervalMs)*time.Millisecond, r.config.VerificationOutputFile)
+		go verifier.Start(writerCtx)
+	}
+	
+	// One fully traced request per progress interval, recorded separately
+	var canaries *CanaryRecorder
+	if r.config.CanaryTracing {
+		canaries = NewCanaryRecorder(handler, r.config.Query, r.config.QueryName, r.params,
+			time.Duration(r.config.ProgressReportIntervalMs)*time.Millisecond, artifactBase(r.config.OutputFile)+".canaries.jsonl",
+			r.rngFor("canary", 0))
+		go canaries.Start(writerCtx)
+	}
+	
+	startTime := time.Now()
+	// Cache-busting nonces come from the clock, not the seed, so a rerun with the
+	// same seed cannot hit results cached by the previous run
+	runNonce := startTime.UnixNano()
+	endTime := startTime.Add(time.Duration(r.config.DurationMs) * time.Millisecond)
+	timeline := NewLatencyTimeline(startTime, r.rngFor("timeline", 0))
+	latencies := NewLatencyRecorder(r.config.LatencySignificantDigits)
+	failedLatencies := NewLatencyRecorder(r.config.LatencySignificantDigits)
+	correctedLatencies := NewLatencyRecorder(r.config.LatencySignificantDigits)
+	correctedFailedLatencies := NewLatencyRecorder(r.config.LatencySignificantDigits)
+	firstRowLatencies := NewLatencyRecorder(r.config.LatencySignificantDigits)
+	serverLatencies := NewLatencyRecorder(r.config.LatencySignificantDigits)
+	
+	// Soak runs checkpoint their stats periodically instead of only at the end
+	var checkpoints *CheckpointRecorder
+	if r.config.CheckpointIntervalMs > 0 {
+		checkpoints = NewCheckpointRecorder(checkpointPath(r.config.OutputFile),
+			time.Duration(r.config.CheckpointIntervalMs)*time.Millisecond, r.config.LatencySignificantDigits, latencies)
+		go checkpoints.Start(writerCtx, startTime)
+	}
+	intervalMs := float64(r.config.RequestIntervalMs)
+	jitter := time.Duration(r.config.RequestJitterMs) * time.Millisecond
+	queryNameStats := NewQueryNameStats(r.config.LatencySignificantDigits)
+	var clusterStats *QueryNameStats
+	if len(r.config.Clusters) > 0 {
+		clusterStats = NewClusterStats(r.config.LatencySignificantDigits)
+	}
+	rolling := NewRollingStats(r.config.LatencySignificantDigits)
+	rowCounts := NewRowCountStats()
+	errorStats := NewErrorStats()
+	outlierStats := NewOutlierStats(r.config.OutlierTopN, r.config.OutlierThresholdMs)
+	allocStats := &AllocStats{}
+	planCacheStats := NewPlanCacheStats()
+	var stageStats *StageStats
+	if len(r.thinkTimeStages) > 0 {
+		stageStats = NewStageStats(len(r.thinkTimeStages), r.config.LatencySignificantDigits)
+	}
+	var workerStats *WorkerStats
+	if r.config.PerWorkerStats {
+		workerStats = NewWorkerStats(r.config.Threads, r.config.LatencySignificantDigits)
+	}
+	
+	var wg sync.WaitGroup
+	var participatingWorkers int64
+	var goodputCount int64
+	var retriedRequests, totalRetries int64
+	var rampRequests int64
+	var canceledCount int64
+	var cooldownCount int64
+	rampEnd := startTime.Add(time.Duration(r.config.RampUpMs) * time.Millisecond)
+	
+	// Cancelled early by a shutdown signal or the stall watchdog, or once the
+	// cooldown after the measurement window has passed
+	runCtx, runCancel := context.WithCancel(ctx)
+	defer runCancel()
+	if r.config.CooldownMs > 0 {
+		cooldownEnd := time.AfterFunc(time.Until(endTime.Add(time.Duration(r.config.CooldownMs)*time.Millisecond)), runCancel)
+		defer cooldownEnd.Stop()
+	}
+	
+	// Open-model runs dispatch requests on one fixed schedule shared by all workers,
+	// at the same aggregate rate the closed model targets. The dispatcher takes the
+	// in-flight slots itself, so it sheds requests instead of queueing them while
+	// the cluster is saturated.
+	var dispatcher *OpenLoopDispatcher
+	dispatchLag := &SchedulingLatencyStats{}
+	if r.config.LoadModel == LoadModelOpen {
+		dispatcher = NewOpenLoopDispatcher(r.dispatchInterval(), inFlight)
+		go dispatcher.Start(runCtx, startTime, endTime)
+	}
+	if queryLog != nil {
+		go queryLog.Start(runCtx)
+	}
+	
+	// A target throughput replaces per-worker interval pacing with one shared limiter
+	var limiter *rate.Limiter
+	if r.config.TargetRPS > 0 && dispatcher == nil {
+		limiter = rate.NewLimiter(rate.Limit(r.config.TargetRPS), 1)
+	}
+	// A per-worker rate gives each worker, and so all of its lanes, its own limiter
+	var workerLimiters []*rate.Limiter
+	if r.config.PerWorkerRPS > 0 {
+		workerLimiters = make([]*rate.Limiter, r.config.Threads)
+		for i := range workerLimiters {
+			workerLimiters[i] = rate.NewLimiter(rate.Limit(r.config.PerWorkerRPS), 1)
+		}
+	}
+	
+	// Start worker threads. With a pipeline depth above 1 each worker runs that
+	// many lanes, each keeping one request in flight, so the worker never waits
+	// for one result before issuing the next request. Lanes share the worker's ID,
+	// ramp-up slot and per-worker stats.
+	participated := make([]int32, r.config.Threads)
+	for i := 0; i < r.config.Threads; i++ {
+		for lane := 0; lane < r.config.PipelineDepth; lane++ {
+			wg.Add(1)
+			go func(workerID, lane int) {
+				defer wg.Done()
+				
+				workerLimiter := limiter
+				if workerLimiters != nil {
+					workerLimiter = workerLimiters[workerID]
+				}
+				
+				// Connection-churn mode gives each worker its own recycled connection
+				workerHandler := handler
+				if r.config.ReconnectEvery > 0 {
+					reconnecting, err := NewReconnectingSDKHandler(r.createSDKHandler, r.config.ReconnectEvery, r.config.SDKType)
+					if err != nil {
+						logWarnf("Worker %d failed to open its connection: %v", workerID, err)
+						return
+					}
+					defer reconnecting.Close()
+					workerHandler = reconnecting
+				}
+				
+				// A worker participates once one of its lanes has issued a request and
+				// run to completion
+				issued := false
+				defer func() {
+					if issued && atomic.CompareAndSwapInt32(&participated[workerID], 0, 1) {
+						atomic.AddInt64(&participatingWorkers, 1)
+					}
+				}()
+				
+				// Ramp-up brings workers online one by one, evenly spread over the window
+				if r.config.RampUpMs > 0 {
+					offset := time.Duration(r.config.RampUpMs) * time.Millisecond * time.Duration(workerID) / time.Duration(r.config.Threads)
+					if !sleepContext(runCtx, time.Until(startTime.Add(offset))) {
+						return
+					}
+				}
+				
+				nextExecutionTime := time.Now()
+				
+				// An open-model job holds the in-flight slot the dispatcher took for it,
+				// so a job abandoned before its request is issued gives the slot back
+				releaseJob := func() {
+					if dispatcher != nil && inFlight != nil {
+						<-inFlight
+					}
+				}
+				
+				// Think-time stages take precedence over a single run-wide think time
+				var thinkTime *ThinkTimeSampler
+				var stageSamplers []*ThinkTimeSampler
+				rng := r.rngFor("worker", workerID)
+				if lane > 0 {
+					rng = r.rngFor("pipeline", workerID*r.config.PipelineDepth+lane)
+				}
+				if len(r.thinkTimeStages) > 0 {
+					stageSamplers = newStageSamplers(r.thinkTimeStages, rng)
+				} else if r.config.ThinkTimeMs > 0 {
+					thinkTime = NewThinkTimeSampler(r.config.ThinkTimeDistribution, r.config.ThinkTimeMs, rng)
+				}
+				
+				for time.Now().Before(endTime) && runCtx.Err() == nil {
+					var scheduled time.Time
+					if dispatcher != nil {
+						var ok bool
+						select {
+						case scheduled, ok = <-dispatcher.Jobs():
+							if !ok {
+								return
+							}
+						case <-runCtx.Done():
+							return
+						}
+					} else if workerLimiter != nil {
+						if err := workerLimiter.Wait(runCtx); err != nil {
+							return
+						}
+					}
+					
+					// Claiming the request slot atomically keeps the total at the cap
+					// however many workers race for the last one
+					if claimed := atomic.AddInt64(&requestCount, 1); r.config.MaxRequests > 0 && claimed > r.config.MaxRequests {
+						atomic.AddInt64(&requestCount, -1)
+						releaseJob()
+						return
+					}
+					
+					query, queryName := r.config.Query, r.config.QueryName
+					var timeout, recordedTimeout time.Duration
+					var seq int64
+					var params *QueryParameters
+					variant := 0
+					logPaced := false
+					if replay != nil {
+						entry, ok := replay.Next()
+						if !ok {
+							atomic.AddInt64(&requestCount, -1)
+							releaseJob()
+							return
+						}
+						query, queryName, seq = entry.Query, entry.QueryName, entry.SequenceNumber
+						variant, params = entry.QueryVariant, entry.Parameters
+						timeout = time.Duration(entry.TimeoutMs) * time.Millisecond
+						recordedTimeout = timeout
+					} else if queryLog != nil {
+						entry, ok := queryLog.Next()
+						if !ok {
+							atomic.AddInt64(&requestCount, -1)
+							releaseJob()
+							return
+						}
+						seq = atomic.AddInt64(&r.sequenceCounter, 1)
+						query, queryName = entry.Query, queryLogQueryName
+						// Recorded offsets are the schedule; a worker that picks a query up
+						// late issues it immediately
+						if entry.HasOffset {
+							logPaced = true
+							due := startTime.Add(entry.Offset)
+							if !due.Before(endTime) || !sleepContext(runCtx, time.Until(due)) {
+								atomic.AddInt64(&requestCount, -1)
+								releaseJob()
+								return
+							}
+						}
+					} else {
+						seq = atomic.AddInt64(&r.sequenceCounter, 1)
+						entry := r.queryMix.Pick(rng)
+						query, queryName, timeout = entry.Query, entry.Name, entry.Timeout()
+						recordedTimeout = timeout
+					}
+					if timeout <= 0 {
+						timeout = r.queryTimeout()
+					}
+					
+					issued = true
+					
+					// Time blocked on the limiter is self-imposed throttling, not server latency.
+					// Open-model requests already hold the slot the dispatcher took for them.
+					var semaphoreWait time.Duration
+					if inFlight != nil && dispatcher == nil {
+						waitStart := time.Now()
+						inFlight <- struct{}{}
+						semaphoreWait = time.Since(waitStart)
+						atomic.AddInt64(&semaphoreWaitNanos, semaphoreWait.Nanoseconds())
+					}
+					
+					// Plan-cache pressure mode cycles through structurally distinct variants.
+					// A replayed query was recorded with both rewrites already applied.
+					if r.config.DistinctQueries > 0 && replay == nil {
+						variant = int(seq%int64(r.config.DistinctQueries)) + 1
+						query = planVariantQuery(query, variant)
+					}
+					if r.config.CacheBusting && replay == nil {
+						query = cacheBustingQuery(query, runNonce, seq)
+					}
+					// Replayed requests resend the recorded parameters; records without
+					// any, including ones from before parameters were recorded, render afresh
+					if params == nil {
+						rendered := r.renderParams(seq, rng)
+						params = &rendered
+					}
+					
+					// The stage is fixed by when the request was issued
+					stage := 0
+					if stageSamplers != nil {
+						stage = thinkTimeStageAt(r.thinkTimeStages, time.Since(startTime))
+						thinkTime = stageSamplers[stage]
+					}
+					
+					// ReadMemStats stops the world, so only a subset of requests is measured.
+					// The counters are process-wide and include concurrent workers' allocations.
+					sampleAllocs := r.config.AllocSampleEvery > 0 && seq%int64(r.config.AllocSampleEvery) == 0
+					var memBefore runtime.MemStats
+					if sampleAllocs {
+						runtime.ReadMemStats(&memBefore)
+					}
+					
+					// Time queued behind slower requests counts against the open-model schedule
+					var lag time.Duration
+					if dispatcher != nil {
+						lag = time.Since(scheduled)
+						dispatchLag.Add(lag)
+					}
+					
+					inRamp := time.Now().Before(rampEnd)
+					result := workerHandler.ExecuteQuery(runCtx, query, queryName, seq, timeout, *params)
+					// A request abandoned at shutdown says nothing about the cluster, so it
+					// is left out of every count instead of showing up as a failure
+					if result.ErrorCategory == ErrorCategoryCanceled {
+						if inFlight != nil {
+							<-inFlight
+						}
+						atomic.AddInt64(&requestCount, -1)
+						atomic.AddInt64(&canceledCount, 1)
+						return
+					}
+					atomic.AddInt64(&completedCount, 1)
+					result.ValidateRowCount(r.config.ExpectedMinRows, r.config.ExpectedMaxRows)
+					if result.ErrorCategory == ErrorCategoryValidation {
+						logWarnf("Query #%d failed validation: %s", seq, result.ErrorMessage)
+					}
+					// No new requests start after the window; these are the ones it drains
+					if r.config.CooldownMs > 0 && time.Now().After(endTime) {
+						result.Cooldown = true
+						atomic.AddInt64(&cooldownCount, 1)
+					}
+					if inRamp {
+						result.RampUp = true
+						atomic.AddInt64(&rampRequests, 1)
+					}
+					if dispatcher != nil {
+						result.DispatchLagMs = float64(lag.Nanoseconds()) / 1_000_000.0
+					}
+					
+					if sampleAllocs {
+						var memAfter runtime.MemStats
+						runtime.ReadMemStats(&memAfter)
+						result.AllocBytes = memAfter.TotalAlloc - memBefore.TotalAlloc
+						result.Allocs = memAfter.Mallocs - memBefore.Mallocs
+						allocStats.Add(result.AllocBytes, result.Allocs)
+					}
+					result.Query = query
+					result.QueryVariant = variant
+					if !params.IsEmpty() {
+						result.Parameters = params
+					}
+					result.TimeoutMs = recordedTimeout.Milliseconds()
+					result.ResultFormat = r.config.ResultFormat
+					if r.config.EpochMs > 0 {
+						result.SetEpoch(r.config.EpochMs)
+					}
+					atomic.AddInt64(&busyNanos, result.DurationNanos)
+					
+					if inFlight != nil {
+						<-inFlight
+						result.SemaphoreWaitMs = float64(semaphoreWait.Nanoseconds()) / 1_000_000.0
+					}
+					
+					if result.IsGoodput() {
+						atomic.AddInt64(&goodputCount, 1)
+					}
+					if result.RetryCount > 0 {
+						atomic.AddInt64(&retriedRequests, 1)
+						atomic.AddInt64(&totalRetries, int64(result.RetryCount))
+					}
+					queryNameStats.Add(result)
+					outlierStats.Add(result)
+					if checkpoints != nil {
+						checkpoints.Add(result)
+					}
+					if clusterStats != nil {
+						clusterStats.Add(result)
+					}
+					if result.Success {
+						atomic.AddInt64(&successCount, 1)
+						timeline.Add(result)
+						latencies.Record(result.DurationMs)
+						rolling.Add(result.DurationMs)
+						if r.config.CorrectCoordinatedOmission {
+							correctedLatencies.RecordCorrected(result.DurationMs, intervalMs)
+						}
+						rowCounts.Add(result.RowCount)
+						if result.RowCount > 0 {
+							firstRowLatencies.Record(result.FirstRowLatencyMs)
+						}
+						if result.ServerElapsedMs > 0 {
+							serverLatencies.Record(result.ServerElapsedMs)
+						}
+						if variant > 0 {
+							planCacheStats.Add(result)
+						}
+					} else {
+						failedLatencies.Record(result.DurationMs)
+						if r.config.PercentilesIncludeFailures {
+							rolling.Add(result.DurationMs)
+						}
+						if r.config.CorrectCoordinatedOmission {
+							correctedFailedLatencies.RecordCorrected(result.DurationMs, intervalMs)
+						}
+						errorStats.Add(result.ErrorMessage, result.ErrorCategory)
+					}
+					if result.RequestBytes > 0 {
+						sizeStats.Add(result.ResponseBytes, result.DurationMs)
+					}
+					if r.config.ReconnectEvery > 0 {
+						reconnectStats.Add(result)
+					}
+					if result.Phases != nil {
+						phaseStats.Add(result.QueryName, result.Phases)
+					}
+					if stageSamplers != nil {
+						active := r.thinkTimeStages[stage]
+						result.Stage = stage + 1
+						result.ThinkTimeMeanMs = active.MeanMs
+						result.ThinkTimeDist = active.Distribution
+						stageStats.Add(result)
+					}
+					if workerStats != nil {
+						worker := workerID
+						result.WorkerID = &worker
+						workerStats.Add(result)
+					}
+					
+					// Closed-loop think time replaces interval pacing when configured:
+					// the next request starts a think time after this one completed
+					var pause time.Duration
+					if thinkTime != nil {
+						pause = thinkTime.Next()
+						result.ThinkTimeMs = float64(pause.Nanoseconds()) / 1_000_000.0
+					}
+					
+					writer.WriteResult(result)
+					
+					// The dispatcher, a limiter or the query log already paces these requests
+					if dispatcher != nil || workerLimiter != nil || logPaced {
+						continue
+					}
+					
+					if thinkTime != nil {
+						intendedStart := time.Now().Add(pause)
+						if sleepContext(runCtx, pause) {
+							schedulingStats.Add(time.Since(intendedStart))
+						}
+						continue
+					}
+					
+					// Fixed coordinated omission timing. Jitter moves each start within its
+					// slot, but the slots advance by the nominal interval so it never drifts.
+					nextExecutionTime = nextExecutionTime.Add(time.Duration(r.config.RequestIntervalMs) * time.Millisecond)
+					intendedStart := nextExecutionTime
+					if jitter > 0 {
+						intendedStart = intendedStart.Add(time.Duration(rng.Int63n(int64(2*jitter)+1)) - jitter)
+					}
+					sleepTime := time.Until(intendedStart)
+					if sleepTime > 0 && sleepContext(runCtx, sleepTime) {
+						// Oversleep past the intended start is scheduler delay, not query time
+						schedulingStats.Add(time.Since(intendedStart))
+					}
+				}
+			}(i, lane)
+		}
+	}
+	
+	// Monitor progress
+	var statsServer *StatsServer
+	if r.config.MetricsHTTPPort > 0 {
+		statsServer = NewStatsServer(r.config.MetricsHTTPPort, handler.GetSDKType(), startTime, &requestCount, &successCount)
+		statsServer.Start()
+		defer statsServer.Close()
+	}
+	// The bar replaces the progress log lines only on a terminal, so piped output is unchanged
+	var progressBar *ProgressBar
+	if r.config.ProgressBar && stderrIsTerminal() {
+		progressBar = NewProgressBar(endTime.Sub(startTime))
+	}
+	// The run can end before endTime (request cap, signal, stall, exhausted replay),
+	// so the monitor is stopped explicitly once the workers are done
+	progressCtx, stopProgress := context.WithCancel(runCtx)
+	progressStopped := make(chan struct{})
+	go func() {
+		defer close(progressStopped)
+		r.monitorProgress(progressCtx, startTime, endTime, &requestCount, &successCount, rolling, statsServer, progressBar)
+	}()
+	
+	var stalled int32
+	if r.config.StallTimeoutMs > 0 {
+		go r.watchForStall(runCtx, endTime, &completedCount, func() {
+			atomic.StoreInt32(&stalled, 1)
+			runCancel()
+		})
+	}
+	
+	wg.Wait()
+	stopProgress()
+	<-progressStopped
+	progressBar.Finish()
+	testElapsed := time.Since(startTime)
+	clientRuntime := runtimeStats()
+	
+	// ✅ FIXED: Proper shutdown sequence
+	log.Printf("All workers finished, shutting down metrics writer...")
+	writerCancel() // Signal writer to stop accepting new writes
+	writer.Wait()  // Wait for writer to finish processing all queued results
+	if verifier != nil {
+		verifier.Wait()
+	}
+	if canaries != nil {
+		canaries.Wait()
+	}
+	if checkpoints != nil {
+		checkpoints.Wait()
+	}
+	
+	// Final summary
+	totalRequests := atomic.LoadInt64(&requestCount)
+	totalSuccesses := atomic.LoadInt64(&successCount)
+	successRate := float64(0)
+	if totalRequests > 0 {
+		successRate = (float64(totalSuccesses) * 100.0) / float64(totalRequests)
+	}
+	
+	log.Printf("✅ %s SDK Test Complete:", handler.GetSDKType())
+	aborted := ""
+	if atomic.LoadInt32(&stalled) == 1 {
+		aborted = fmt.Sprintf("no progress for %.0fs", float64(r.config.StallTimeoutMs)/1000.0)
+	} else if ctx.Err() != nil {
+		aborted = "interrupted by signal, partial results"
+	}
+	if aborted != "" {
+		log.Printf("   Aborted: %s", aborted)
+	}
+	log.Printf("   Total Requests: %d", totalRequests)
+	canceled := atomic.LoadInt64(&canceledCount)
+	if canceled > 0 {
+		log.Printf("   Canceled at Shutdown: %d in-flight requests, excluded from the totals", canceled)
+	}
+	cooldown := atomic.LoadInt64(&cooldownCount)
+	if cooldown > 0 {
+		log.Printf("   Completed in Cooldown: %d requests finished after the duration (flagged cooldown)", cooldown)
+	}
+	var connectionTimings []ConnectionTiming
+	var connectionTimeMs, slowestConnectionMs float64
+	if timer, ok := handler.(connectionTimer); ok {
+		connectionTimings = timer.ConnectionTimings()
+	}
+	for _, timing := range connectionTimings {
+		connectionTimeMs += timing.ConnectionTimeMs
+		if timing.ConnectionTimeMs > slowestConnectionMs {
+			slowestConnectionMs = timing.ConnectionTimeMs
+		}
+	}
+	if len(connectionTimings) > 0 {
+		log.Printf("   Connection Time: %.2fms to open %d connections before the run (slowest %.2fms)",
+			connectionTimeMs, len(connectionTimings), slowestConnectionMs)
+	}
+	var healthReconnects []HealthReconnectEvent
+	if reporter, ok := handler.(healthReporter); ok {
+		healthReconnects = reporter.HealthReconnects()
+	}
+	if len(healthReconnects) > 0 {
+		failed := 0
+		for _, event := range healthReconnects {
+			if event.Error != "" {
+				failed++
+			}
+		}
+		log.Printf("   Health Reconnects: %d after failed health checks (%d could not reconnect)", len(healthReconnects), failed)
+	}
+	if r.config.MaxRequests > 0 && totalRequests >= r.config.MaxRequests {
+		log.Printf("   Request Cap: reached %d requests after %.1fs of the %dms duration",
+			r.config.MaxRequests, testElapsed.Seconds(), r.config.DurationMs)
+	}
+	log.Printf("   Success Rate: %.2f%%", successRate)
+	goodput := atomic.LoadInt64(&goodputCount)
+	usefulShare := float64(0)
+	if totalRequests > 0 {
+		usefulShare = float64(goodput) * 100.0 / float64(totalRequests)
+	}
+	log.Printf("   Throughput: %.2f attempted RPS | %.2f goodput RPS (%.2f%% of attempts useful)",
+		float64(totalRequests)/testElapsed.Seconds(), float64(goodput)/testElapsed.Seconds(), usefulShare)
+	if r.config.RampUpMs > 0 {
+		log.Printf("   Ramp-Up: %d requests issued during the %dms ramp (tagged ramp_up)",
+			atomic.LoadInt64(&rampRequests), r.config.RampUpMs)
+	}
+	if r.config.MaxRetries > 0 {
+		log.Printf("   Retries: %d requests retried, %d retries in total",
+			atomic.LoadInt64(&retriedRequests), atomic.LoadInt64(&totalRetries))
+	}
+	percentiles := r.reportLatencyPercentiles(latencies, failedLatencies)
+	var correctedPercentiles *LatencyPercentiles
+	if r.config.CorrectCoordinatedOmission {
+		corrected := r.reportCorrectedPercentiles(correctedLatencies, correctedFailedLatencies)
+		correctedPercentiles = &corrected
+	}
+	var firstRowPercentiles *LatencyPercentiles
+	if firstRowLatencies.Count() > 0 {
+		firstRow := reportFirstRowPercentiles(firstRowLatencies)
+		firstRowPercentiles = &firstRow
+	}
+	var serverPercentiles *LatencyPercentiles
+	if serverLatencies.Count() > 0 {
+		server := reportServerPercentiles(serverLatencies)
+		serverPercentiles = &server
+	}
+	querySummaries := queryNameStats.Summaries(PercentileCalculator{IncludeFailures: r.config.PercentilesIncludeFailures})
+	if len(querySummaries) > 1 {
+		for _, query := range querySummaries {
+			log.Printf("   Query %s: %d requests, %.2f%% success | p50 %.2fms | p95 %.2fms | p99 %.2fms | max %.2fms",
+				query.Name, query.TotalRequests, query.SuccessRate,
+				query.Latency.P50Ms, query.Latency.P95Ms, query.Latency.P99Ms, query.Latency.MaxMs)
+		}
+	}
+	var clusterSummaries []QuerySummary
+	if clusterStats != nil {
+		clusterSummaries = clusterStats.Summaries(PercentileCalculator{IncludeFailures: r.config.PercentilesIncludeFailures})
+		for _, cluster := range clusterSummaries {
+			log.Printf("   Cluster %s: %d requests, %.2f%% success | p50 %.2fms | p95 %.2fms | p99 %.2fms | max %.2fms",
+				cluster.Name, cluster.TotalRequests, cluster.SuccessRate,
+				cluster.Latency.P50Ms, cluster.Latency.P95Ms, cluster.Latency.P99Ms, cluster.Latency.MaxMs)
+		}
+	}
+	participants := atomic.LoadInt64(&participatingWorkers)
+	log.Printf("   Participating Workers: %d/%d", participants, r.config.Threads)
+	var workerSummaries []WorkerSummary
+	if workerStats != nil {
+		workerSummaries = workerStats.Summaries()
+		reportWorkers(workerSummaries)
+	}
+	effectiveConcurrency := float64(atomic.LoadInt64(&busyNanos)) / float64(testElapsed.Nanoseconds())
+	if r.config.PipelineDepth > 1 {
+		log.Printf("   Effective Concurrency: %.2f (of %d threads x %d pipeline depth)",
+			effectiveConcurrency, r.config.Threads, r.config.PipelineDepth)
+	} else {
+		log.Printf("   Effective Concurrency: %.2f (of %d threads)", effectiveConcurrency, r.config.Threads)
+	}
+	// Open-model requests never wait for a slot; they are shed instead
+	if inFlight != nil && dispatcher == nil {
+		totalWait := time.Duration(atomic.LoadInt64(&semaphoreWaitNanos))
+		avgWaitMs := float64(0)
+		if totalRequests > 0 {
+			avgWaitMs = float64(totalWait.Nanoseconds()) / 1_000_000.0 / float64(totalRequests)
+		}
+		log.Printf("   Semaphore Wait: %.2fs total, %.3fms avg per request (max in-flight %d)",
+			totalWait.Seconds(), avgWaitMs, r.config.MaxInFlight)
+	}
+	if r.config.ReconnectEvery > 0 {
+		reconnects, avgReconnectMs, avgAfterMs, avgOtherMs := reconnectStats.Snapshot()
+		log.Printf("   Reconnects: %d (avg %.2fms to reconnect)", reconnects, avgReconnectMs)
+		log.Printf("   Latency After Reconnect: %.2fms avg vs %.2fms for other requests", avgAfterMs, avgOtherMs)
+	}
+	if r.config.CapturePhases {
+		r.reportPhases(phaseStats)
+	}
+	if verifier != nil {
+		runs, failures, mismatches := verifier.Snapshot()
+		log.Printf("   Verification: %d runs | %d failed | %d differed from baseline | written to %s",
+			runs, failures, mismatches, r.config.VerificationOutputFile)
+	}
+	log.Printf("   Client Runtime: heap %.1f MiB | %d GCs, %.2fms total pause, %.2fms max recent pause | %d goroutines",
+		float64(clientRuntime.HeapAllocBytes)/(1<<20), clientRuntime.NumGC,
+		clientRuntime.GCPauseTotalMs, clientRuntime.GCPauseMaxMs, clientRuntime.Goroutines)
+	if r.config.AllocSampleEvery > 0 {
+		if samples, avgBytes, avgAllocs := allocStats.Snapshot(); samples > 0 {
+			log.Printf("   Client Allocations: %.0f bytes, %.0f allocations per request (avg over %d sampled requests)",
+				avgBytes, avgAllocs, samples)
+		}
+	}
+	if canaries != nil {
+		log.Printf("   Canary Traces: %d written to %s.canaries.jsonl", canaries.Count(), artifactBase(r.config.OutputFile))
+	}
+	if checkpoints != nil {
+		log.Printf("   Checkpoints: %d written to %s", checkpoints.Count(), checkpointPath(r.config.OutputFile))
+	}
+	if stageStats != nil {
+		r.reportStages(stageStats)
+	}
+	if r.config.DistinctQueries > 0 {
+		reportPlanCache(planCacheStats.Summary())
+	}
+	reportRowCounts(rowCounts)
+	reportTopErrors(errorStats)
+	outliers := outlierStats.Summary()
+	reportOutliers(outliers)
+	reportLatencyRegimes(timeline.DetectRegimes())
+	if wakeups, avgDelay, maxDelay := schedulingStats.Snapshot(); wakeups > 0 {
+		log.Printf("   Scheduling Latency: %.3fms avg, %.3fms max over %d wakeups",
+			float64(avgDelay.Nanoseconds())/1_000_000.0, float64(maxDelay.Nanoseconds())/1_000_000.0, wakeups)
+	}
+	var shed int64
+	if dispatcher != nil {
+		dispatched, peakDepth, unserved := dispatcher.Snapshot()
+		_, avgLag, maxLag := dispatchLag.Snapshot()
+		log.Printf("   Open Model: %d dispatched, peak queue depth %d, %d unserved at end", dispatched, peakDepth, unserved)
+		log.Printf("   Dispatch Lag: %.3fms avg, %.3fms max",
+			float64(avgLag.Nanoseconds())/1_000_000.0, float64(maxLag.Nanoseconds())/1_000_000.0)
+		if shed = dispatcher.Shed(); shed > 0 {
+			logWarnf("Shed: %d scheduled requests skipped with %d already in flight; the system under test saturated",
+				shed, r.config.MaxInFlight)
+		}
+	}
+	if r.config.SizeSampleEvery > 0 {
+		if correlation, ok := sizeStats.Correlation(); ok {
+			log.Printf("   Response Size vs Latency: r=%.3f over %d sampled requests (avg %.0f bytes)",
+				correlation, sizeStats.Count(), sizeStats.MeanBytes())
+		} else {
+			log.Printf("   Response Size vs Latency: not enough varied samples (%d sampled)", sizeStats.Count())
+		}
+	}
+	log.Printf("   Results written: %d", writer.GetWrittenCount())
+	dropped := writer.GetDroppedCount()
+	var skipped int64
+	if degrading != nil {
+		sampled := degrading.SampledDuration()
+		skipped = degrading.SkippedCount()
+		log.Printf("   Writer Sampling: 1 in %d for %.1fs (%.1f%% of run), %d results not recorded",
+			r.config.WriterDegradeSampleEvery, sampled.Seconds(),
+			sampled.Seconds()*100.0/testElapsed.Seconds(), skipped)
+	}
+	if sampling != nil {
+		skipped += sampling.SkippedCount()
+		log.Printf("   Result Sampling: %g%% sampled, %d results not written to the raw output",
+			r.config.SampleRate*100, sampling.SkippedCount())
+	}
+	if spilling != nil {
+		log.Printf("   Writer Spill: peak %d of %d results held in memory beyond the %d-result queue",
+			spilling.PeakSpill(), r.config.WriterSpillSize, r.config.WriterBufferSize)
+	}
+	if dropped > 0 {
+		logWarnf("Results dropped: %d (writer queue full); the raw output is missing these requests", dropped)
+	}
+	// Every issued request produces one result, so anything beyond the known
+	// losses went missing between the workers and the output
+	if unaccounted := totalRequests - writer.GetWrittenCount() - dropped - skipped; unaccounted != 0 {
+		logWarnf("Result reconciliation: %d requests issued, %d written, %d dropped, %d skipped by sampling; %d unaccounted for",
+			totalRequests, writer.GetWrittenCount(), dropped, skipped, unaccounted)
+	}
+	if r.config.OutputTimeBucketMs > 0 {
+		log.Printf("   Raw data written to: %s, split into one file per %dms bucket (UTC bucket start before the extension)",
+			r.config.OutputFile, r.config.OutputTimeBucketMs)
+	} else {
+		log.Printf("   Raw data written to: %s", r.config.OutputFile)
+	}
+	if bucketMs := r.aggregateBucketMs(); bucketMs > 0 {
+		log.Printf("   Time Buckets: %dms aggregates written to %s", bucketMs, timeBucketReportPath(r.config.OutputFile))
+	}
+	
+	// Every gate is checked and logged, so one run reports all violations. The
+	// SLO outcomes are recorded in the summary as well.
+	var violations []error
+	var sloResults []SLOResult
+	if len(r.slos) > 0 {
+		var err error
+		if sloResults, err = r.checkSLOs(latencies, failedLatencies); err != nil {
+			violations = append(violations, err)
+		}
+	}
+	if r.config.SLAMinSuccessRate > 0 {
+		if err := r.checkSuccessRateSLA(successRate); err != nil {
+			violations = append(violations, err)
+		}
+	}
+	
+	latencyIncludes := "successful requests"
+	if r.config.PercentilesIncludeFailures {
+		latencyIncludes = "all requests"
+	}
+	report := &SummaryReport{
+		SDKType:          handler.GetSDKType(),
+		QueryName:        r.config.QueryName,
+		RunTimestamp:     r.config.RunTimestamp,
+		RandomSeed:       r.seed,
+		StartTimeMs:      startTime.UnixMilli(),
+		TestDurationMs:   float64(testElapsed.Nanoseconds()) / 1_000_000.0,
+		TotalRequests:    totalRequests,
+		Successes:        totalSuccesses,
+		Failures:         totalRequests - totalSuccesses,
+		SuccessRate:      successRate,
+		ThroughputRPS:    float64(totalRequests) / testElapsed.Seconds(),
+		GoodputRPS:       float64(goodput) / testElapsed.Seconds(),
+		LatencyIncludes:  latencyIncludes,
+		CacheBusting:     r.config.CacheBusting,
+		ScanConsistency:  r.scanConsistency(),
+		Latency:          percentiles,
+		LatencyCorrected: correctedPercentiles,
+		LatencyFirstRow:  firstRowPercentiles,
+		LatencyServer:    serverPercentiles,
+		Queries:          querySummaries,
+		Clusters:         clusterSummaries,
+		Workers:          workerSummaries,
+		ErrorCategories:  errorStats.Categories(),
+		Outliers:         outliers,
+		SLOs:             NewSLOSummaries(sloResults),
+		ResultsWritten:   writer.GetWrittenCount(),
+		ResultsDropped:   dropped,
+		ResultsSkipped:   skipped,
+		Canceled:         canceled,
+		Cooldown:         cooldown,
+		ConnectionTimeMs: connectionTimeMs,
+		Connections:      connectionTimings,
+		HealthReconnects: healthReconnects,
+		Runtime:          clientRuntime,
+		Shed:             shed,
+		RawOutputFile:    r.config.OutputFile,
+		Aborted:          aborted,
+	}
+	if err := report.WriteFile(summaryReportPath(r.config.OutputFile)); err != nil {
+		logErrorf("Failed to write summary report: %v", err)
+	} else {
+		log.Printf("   Summary written to: %s", summaryReportPath(r.config.OutputFile))
+		if r.config.GenerateReport {
+			if err := NewReportGenerator(r.config.OutputFile).Generate(reportPath(r.config.OutputFile)); err != nil {
+				logErrorf("Failed to write HTML report: %v", err)
+			} else {
+				log.Printf("   HTML report written to: %s", reportPath(r.config.OutputFile))
+			}
+		}
+	}
+	
+	if queryLog != nil {
+		if err := queryLog.Err(); err != nil {
+			return report, err
+		}
+	}
+	if len(violations) > 0 {
+		return report, errors.Join(violations...)
+	}
+	if atomic.LoadInt32(&stalled) == 1 {
+		return report, fmt.Errorf("aborted: %s", aborted)
+	}
+	if participants < int64(r.config.Threads) {
+		if r.config.StrictWorkers {
+			return report, fmt.Errorf("only %d of %d workers issued a request and finished", participants, r.config.Threads)
+		}
+		logWarnf("Only %d of %d workers issued a request and finished; effective concurrency is lower than configured",
+			participants, r.config.Threads)
+	}
+	
+	return report, nil
+}
+
+// reportLatencyPercentiles logs and returns the end-of-run latency percentiles
+func (r *SimpleAnalyticsRunner) reportLatencyPercentiles(success, failure *LatencyRecorder) LatencyPercentiles {
+	calculator := PercentileCalculator{IncludeFailures: r.config.PercentilesIncludeFailures}
+	p := calculator.Calculate(success, failure, summaryPercentiles...)
+	
+	scope := "successful requests"
+	if calculator.IncludeFailures {
+		scope = "all requests"
+	}
+	log.Printf("   Latency (%s): p50 %.2fms | p90 %.2fms | p95 %.2fms | p99 %.2fms | max %.2fms",
+		scope, p[50], p[90], p[95], p[99], p[100])
+	return NewLatencyPercentiles(p)
+}
+
+// reportCorrectedPercentiles logs and returns the latency percentiles corrected
+// for coordinated omission, including the synthesized samples
+func (r *SimpleAnalyticsRunner) reportCorrectedPercentiles(success, failure *LatencyRecorder) LatencyPercentiles {
+	calculator := PercentileCalculator{IncludeFailures: r.config.PercentilesIncludeFailures}
+	p := calculator.Calculate(success, failure, summaryPercentiles...)
+	
+	log.Printf("   Latency (corrected for coordinated omission): p50 %.2fms | p90 %.2fms | p95 %.2fms | p99 %.2fms | max %.2fms",
+		p[50], p[90], p[95], p[99], p[100])
+	return NewLatencyPercentiles(p)
+}
+
+// reportFirstRowPercentiles logs and returns the time-to-first-row percentiles of
+// the successful requests that returned rows
+func reportFirstRowPercentiles(firstRow *LatencyRecorder) LatencyPercentiles {
+	p := firstRow.Percentiles(summaryPercentiles...)
+	log.Printf("   Time to First Row (%d requests with rows): p50 %.2fms | p90 %.2fms | p95 %.2fms | p99 %.2fms | max %.2fms",
+		firstRow.Count(), p[50], p[90], p[95], p[99], p[100])
+	return NewLatencyPercentiles(p)
+}
+
+// reportServerPercentiles logs the server-reported elapsed time percentiles; the
+// gap to the client latency is network and client overhead
+func reportServerPercentiles(server *LatencyRecorder) LatencyPercentiles {
+	p := server.Percentiles(summaryPercentiles...)
+	log.Printf("   Server Elapsed (%d requests with metadata): p50 %.2fms | p90 %.2fms | p95 %.2fms | p99 %.2fms | max %.2fms",
+		server.Count(), p[50], p[90], p[95], p[99], p[100])
+	return NewLatencyPercentiles(p)
+}
+
+// reportPhases logs the average latency breakdown and writes it as a folded-stack file
+func (r *SimpleAnalyticsRunner) reportPhases(phaseStats *PhaseStats) {
+	averages, count := phaseStats.Averages()
+	if count == 0 {
+		log.Printf("   Latency Breakdown: no phase timings reported by the SDK")
+		return
+	}
+	
+	total := averages.ServerQueueAndPlanMs + averages.ServerExecutionMs + averages.NetworkAndStreamMs
+	share := func(ms float64) float64 {
+		if total == 0 {
+			return 0
+		}
+		return ms * 100.0 / total
+	}
+	log.Printf("   Latency Breakdown (avg over %d requests):", count)
+	log.Printf("      Server queue + plan: %.2fms (%.1f%%)", averages.ServerQueueAndPlanMs, share(averages.ServerQueueAndPlanMs))
+	log.Printf("      Server execution:    %.2fms (%.1f%%)", averages.ServerExecutionMs, share(averages.ServerExecutionMs))
+	log.Printf("      Network + stream:    %.2fms (%.1f%%)", averages.NetworkAndStreamMs, share(averages.NetworkAndStreamMs))
+	
+	foldedFile := artifactBase(r.config.OutputFile) + ".phases.folded"
+	if err := phaseStats.WriteFolded(foldedFile); err != nil {
+		logErrorf("Failed to write phase breakdown: %v", err)
+		return
+	}
+	log.Printf("   Phase breakdown written to: %s", foldedFile)
+}
+
+// checkSLOs logs pass/fail for each configured SLO and returns the results, with
+// an error if any failed. The SLOs are checked against the same population as the summary
+// percentiles, so failures count only with BENCHMARK_PERCENTILES_INCLUDE_FAILURES.
+func (r *SimpleAnalyticsRunner) checkSLOs(success, failure *LatencyRecorder) ([]SLOResult, error) {
+	latencies := success
+	scope := "successful requests"
+	if r.config.PercentilesIncludeFailures {
+		latencies = success.Merged(failure)
+		scope = "all requests"
+	}
+	log.Printf("   SLOs (%s):", scope)
+	
+	results := EvaluateSLOs(r.slos, latencies)
+	var failed []string
+	for _, result := range results {
+		status := "✅ PASS"
+		if !result.Passed {
+			status = "❌ FAIL"
+			failed = append(failed, result.Name)
+		}
+		log.Printf("      %s <= %v: %s (actual %.2fms)", result.Name, result.Threshold, status, result.ActualMs)
+	}
+	
+	if len(failed) > 0 {
+		return results, fmt.Errorf("SLO check failed for %s", strings.Join(failed, ", "))
+	}
+	return results, nil
+}
+
+// checkSuccessRateSLA logs pass/fail for the success rate floor and returns an error if it was missed
+func (r *SimpleAnalyticsRunner) checkSuccessRateSLA(successRate float64) error {
+	if successRate < r.config.SLAMinSuccessRate {
+		log.Printf("   Success Rate SLA >= %.2f%%: ❌ FAIL (actual %.2f%%)", r.config.SLAMinSuccessRate, successRate)
+		return fmt.Errorf("success rate SLA failed: %.2f%% is below the %.2f%% floor", successRate, r.config.SLAMinSuccessRate)
+	}
+	log.Printf("   Success Rate SLA >= %.2f%%: ✅ PASS (actual %.2f%%)", r.config.SLAMinSuccessRate, successRate)
+	return nil
+}
+
+// reportRowCounts logs the distribution of rows returned by successful requests
+func reportRowCounts(rowCounts *RowCountStats) {
+	total := rowCounts.Total()
+	if total == 0 {
+		return
+	}
+	
+	p := rowCounts.Percentiles(50, 90, 99, 100)
+	log.Printf("   Row Counts: p50 %d | p90 %d | p99 %d | max %d", p[0], p[1], p[2], p[3])
+	for _, bucket := range rowCounts.Histogram() {
+		label := fmt.Sprintf("%d", bucket.Lower)
+		if bucket.Upper > bucket.Lower {
+			label = fmt.Sprintf("%d-%d", bucket.Lower, bucket.Upper)
+		}
+		log.Printf("      %-15s %d (%.2f%%)", label, bucket.Count, float64(bucket.Count)*100.0/float64(total))
+	}
+}
+
+// reportStages logs request counts and latency percentiles per think-time stage
+func (r *SimpleAnalyticsRunner) reportStages(stageStats *StageStats) {
+	log.Printf("   Latency by Think Time Stage:")
+	for i, stage := range r.thinkTimeStages {
+		requests, latencies := stageStats.Snapshot(i)
+		p := latencies.Percentiles(50, 99)
+		log.Printf("      Stage %d (%dms %s): %d requests | p50 %.2fms | p99 %.2fms",
+			i+1, stage.MeanMs, stage.Distribution, requests, p[50], p[99])
+	}
+}
+
+// reportWorkers logs each worker's requests and latency, and how far apart the
+// busiest and least busy workers are
+func reportWorkers(workers []WorkerSummary) {
+	log.Printf("   Requests by Worker:")
+	busiest, idlest := workers[0], workers[0]
+	for _, worker := range workers {
+		log.Printf("      Worker %d: %d requests | %d successes | p50 %.2fms | p99 %.2fms",
+			worker.WorkerID, worker.TotalRequests, worker.Successes, worker.Latency.P50Ms, worker.Latency.P99Ms)
+		if worker.TotalRequests > busiest.TotalRequests {
+			busiest = worker
+		}
+		if worker.TotalRequests < idlest.TotalRequests {
+			idlest = worker
+		}
+	}
+	if idlest.TotalRequests == 0 {
+		log.Printf("   Worker Imbalance: worker %d issued no requests; worker %d issued %d",
+			idlest.WorkerID, busiest.WorkerID, busiest.TotalRequests)
+		return
+	}
+	log.Printf("   Worker Imbalance: busiest worker %d issued %.2fx the requests of least busy worker %d",
+		busiest.WorkerID, float64(busiest.TotalRequests)/float64(idlest.TotalRequests), idlest.WorkerID)
+}
+
+// reportPlanCache logs how first executions of each query variant compare with repeats
+func reportPlanCache(summary PlanCacheSummary) {
+	log.Printf("   Plan Cache: %d distinct queries executed", summary.DistinctQueries)
+	log.Printf("      First execution:  %.2fms avg", summary.FirstAvgMs)
+	if summary.RepeatCount > 0 {
+		log.Printf("      Repeat execution: %.2fms avg over %d requests", summary.RepeatAvgMs, summary.RepeatCount)
+	}
+	if summary.HasPlanTimings {
+		log.Printf("      Server queue + plan: %.2fms first vs %.2fms repeat",
+			summary.FirstPlanAvgMs, summary.RepeatPlanAvgMs)
+	}
+}
+
+// reportTopErrors logs the most frequent failure reasons
+func reportTopErrors(errorStats *ErrorStats) {
+	total := errorStats.Total()
+	if total == 0 {
+		return
+	}
+	
+	categories := errorStats.Categories()
+	names := make([]string, 0, len(categories))
+	for category := range categories {
+		names = append(names, category)
+	}
+	sort.Slice(names, func(i, j int) bool { return categories[names[i]] > categories[names[j]] })
+	counts := make([]string, len(names))
+	for i, category := range names {
+		counts[i] = fmt.Sprintf("%s %d", category, categories[category])
+	}
+	log.Printf("   Failure Categories: %s", strings.Join(counts, " | "))
+	
+	log.Printf("   Top Failure Reasons (%d failures):", total)
+	for _, entry := range errorStats.Top(topErrorsReported) {
+		log.Printf("      %6d  %s", entry.Count, entry.Message)
+	}
+}
+
+// reportOutliers logs the slowest requests by sequence number, for correlating
+// with server logs, and the count over the outlier threshold
+func reportOutliers(outliers OutlierSummary) {
+	if outliers.ThresholdMs > 0 {
+		log.Printf("   Over Outlier Threshold (%.2fms): %d requests", outliers.ThresholdMs, outliers.OverThreshold)
+	}
+	if len(outliers.Slowest) == 0 {
+		return
+	}
+	log.Printf("   Slowest Requests:")
+	for _, outlier := range outliers.Slowest {
+		outcome := "ok"
+		if !outlier.Success {
+			outcome = "failed"
+		}
+		log.Printf("      seq %-8d %10.2fms  %-6s %s", outlier.SequenceNumber, outlier.DurationMs, outcome, outlier.QueryName)
+	}
+}
+
+// reportLatencyRegimes logs the latency regimes found over the run's timeline
+func reportLatencyRegimes(regimes []LatencyRegime) {
+	if len(regimes) <= 1 {
+		log.Printf("   Latency Regimes: no regime changes detected")
+		return
+	}
+	
+	log.Printf("   Latency Regimes: %d detected", len(regimes))
+	for _, regime := range regimes {
+		log.Printf("      %s - %s | %d requests | mean %.2fms | p50 %.2fms | p99 %.2fms",
+			time.UnixMilli(regime.StartMs).Format(time.TimeOnly), time.UnixMilli(regime.EndMs).Format(time.TimeOnly),
+			regime.Requests, regime.MeanMs, regime.P50Ms, regime.P99Ms)
+	}
+}
+
+// monitorProgress logs progress during the test, or redraws bar when there is one,
+// until endTime or until ctx is cancelled when the run ends early
+func (r *SimpleAnalyticsRunner) monitorProgress(ctx context.Context, startTime, endTime time.Time, requestCount, successCount *int64, rolling *RollingStats, stats *StatsServer, bar *ProgressBar) {
+	interval := time.Duration(r.config.ProgressReportIntervalMs) * time.Millisecond
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if time.Now().After(endTime) {
+				return
+			}
+			
+			elapsed := time.Since(startTime).Seconds()
+			requests := atomic.LoadInt64(requestCount)
+			successes := atomic.LoadInt64(successCount)
+			
+			rps := float64(successes) / elapsed
+			successRate := float64(0)
+			if requests > 0 {
+				successRate = (float64(successes) * 100.0) / float64(requests)
+			}
+			
+			// Percentiles cover only the requests completed during the last interval
+			window := rolling.Snapshot()
+			completed := window.Count()
+			if completed == 0 {
+				stats.PublishInterval(IntervalStats{})
+				if bar != nil {
+					bar.Update(time.Since(startTime), requests, 0, -1)
+					continue
+				}
+				log.Printf("Progress - %ds elapsed | %d requests | %d successes | %.2f%% success | %.2f RPS | no completions this interval",
+					int(elapsed), requests, successes, successRate, rps)
+				continue
+			}
+			p := window.Percentiles(50, 95, 99)
+			stats.PublishInterval(IntervalStats{Completed: completed, P50Ms: p[50], P95Ms: p[95], P99Ms: p[99]})
+			if bar != nil {
+				bar.Update(time.Since(startTime), requests, float64(completed)/interval.Seconds(), p[99])
+				continue
+			}
+			log.Printf("Progress - %ds elapsed | %d requests | %d successes | %.2f%% success | %.2f RPS | interval p50 %.2fms p95 %.2fms p99 %.2fms",
+				int(elapsed), requests, successes, successRate, rps, p[50], p[95], p[99])
+		}
+	}
+}
+
+// renderParams returns the configured parameters for one request, or none
+func (r *SimpleAnalyticsRunner) renderParams(seq int64, rng *rand.Rand) QueryParameters {
+	if r.params == nil {
+		return QueryParameters{}
+	}
+	return r.params.Render(seq, rng)
+}
+
+// validatePacing checks that exactly one of the per-worker interval and the
+// aggregate target throughput sets the request rate
+func validatePacing(config Configuration) error {
+	if config.TargetRPS < 0 {
+		return fmt.Errorf("BENCHMARK_TARGET_RPS must not be negative")
+	}
+	if config.TargetRPS > 0 && config.RequestIntervalMs > 0 {
+		return fmt.Errorf("BENCHMARK_TARGET_RPS and BENCHMARK_REQUEST_INTERVAL_MS are mutually exclusive; set only one")
+	}
+	if config.PerWorkerRPS < 0 {
+		return fmt.Errorf("BENCHMARK_PER_WORKER_RPS must not be negative")
+	}
+	if config.PerWorkerRPS > 0 && (config.TargetRPS > 0 || config.RequestIntervalMs > 0 || config.LoadModel == LoadModelOpen) {
+		return fmt.Errorf("BENCHMARK_PER_WORKER_RPS replaces BENCHMARK_REQUEST_INTERVAL_MS and BENCHMARK_TARGET_RPS, " +
+			"and only applies to the closed load model")
+	}
+	// Think time paces closed-loop workers by itself
+	thinkTime := config.ThinkTimeMs > 0 || config.ThinkTimeStages != ""
+	if config.RequestJitterMs < 0 || config.RequestJitterMs > config.RequestIntervalMs {
+		return fmt.Errorf("BENCHMARK_REQUEST_JITTER_MS must be between 0 and BENCHMARK_REQUEST_INTERVAL_MS")
+	}
+	if config.RequestJitterMs > 0 && (thinkTime || config.LoadModel == LoadModelOpen) {
+		return fmt.Errorf("BENCHMARK_REQUEST_JITTER_MS only applies to closed-loop BENCHMARK_REQUEST_INTERVAL_MS pacing")
+	}
+	if thinkTime {
+		if config.TargetRPS > 0 || config.PerWorkerRPS > 0 {
+			return fmt.Errorf("BENCHMARK_TARGET_RPS and BENCHMARK_PER_WORKER_RPS cannot be combined with think time")
+		}
+		if config.LoadModel == LoadModelOpen {
+			return fmt.Errorf("think time is closed-loop only and cannot be combined with the %s load model", LoadModelOpen)
+		}
+		return nil
+	}
+	if config.TargetRPS == 0 && config.PerWorkerRPS == 0 && config.RequestIntervalMs <= 0 {
+		return fmt.Errorf("one of BENCHMARK_REQUEST_INTERVAL_MS, BENCHMARK_TARGET_RPS or BENCHMARK_PER_WORKER_RPS must be set to a positive value")
+	}
+	return nil
+}
+
+// queryTimeout is the timeout of a request without its own: the per-query
+// timeout when set, otherwise the analytics timeout
+func (r *SimpleAnalyticsRunner) queryTimeout() time.Duration {
+	if r.config.PerQueryTimeoutMs > 0 {
+		return time.Duration(r.config.PerQueryTimeoutMs) * time.Millisecond
+	}
+	return time.Duration(r.config.AnalyticsTimeoutS) * time.Second
+}
+
+// dispatchInterval is the gap between open-model requests: one per target
+// request when a throughput is set, otherwise Threads per request interval
+func (r *SimpleAnalyticsRunner) dispatchInterval() time.Duration {
+	if r.config.TargetRPS > 0 {
+		return time.Second / time.Duration(r.config.TargetRPS)
+	}
+	return time.Duration(r.config.RequestIntervalMs) * time.Millisecond / time.Duration(r.config.Threads)
+}
+
+// sleepContext pauses for d, returning false if ctx was cancelled first
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// watchForStall calls abort if no request completes for the configured stall
+// window. Failed requests count as completed, so a run failing fast is not a
+// stall; the window restarts whenever the completed count moves.
+func (r *SimpleAnalyticsRunner) watchForStall(ctx context.Context, endTime time.Time, completedCount *int64, abort func()) {
+	window := time.Duration(r.config.StallTimeoutMs) * time.Millisecond
+	checkInterval := window / 10
+	if checkInterval < 10*time.Millisecond {
+		checkInterval = 10 * time.Millisecond
+	}
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+	
+	lastCount := atomic.LoadInt64(completedCount)
+	lastProgress := time.Now()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if now.After(endTime) {
+				return
+			}
+			if count := atomic.LoadInt64(completedCount); count != lastCount {
+				lastCount, lastProgress = count, now
+				continue
+			}
+			if now.Sub(lastProgress) >= window {
+				logErrorf("No request completed for %v, aborting the run", window)
+				abort()
+				return
+			}
+		}
+	}
+}
+
+// logConnectionPoolSettings records the connection count and effective idle
+// connection settings in the run header
+func logConnectionPoolSettings(config Configuration) {
+	log.Printf("   Cluster Connections: %d per handler (requests round-robin across them)", config.NumConnections)
+	for _, cluster := range config.Clusters {
+		log.Printf("   Cluster %s: %s (weight %d)", cluster.ID, cluster.ConnectionString, cluster.Weight)
+	}
+	if config.HealthcheckIntervalMs > 0 {
+		log.Printf("   Connection Health Checks: every %dms, reconnect after %d consecutive failures",
+			config.HealthcheckIntervalMs, config.HealthcheckFailures)
+	}
+	if config.KeepAliveIntervalMs > 0 {
+		log.Printf("   Connection Keepalive: one query every %dms per connection, excluded from metrics", config.KeepAliveIntervalMs)
+	}
+	
+	if config.SDKType == "enterprise" {
+		if config.HTTPIdleConnTimeoutMs >= 0 || config.HTTPMaxIdleConnsPerHost > 0 {
+			logWarnf("HTTP idle connection settings are not exposed by the enterprise SDK; using its defaults")
+		}
+		log.Printf("   HTTP Idle Conn Timeout: SDK default")
+		log.Printf("   HTTP Max Idle Conns Per Host: SDK default")
+		return
+	}
+	
+	switch {
+	case config.HTTPIdleConnTimeoutMs < 0:
+		log.Printf("   HTTP Idle Conn Timeout: SDK default")
+	case config.HTTPIdleConnTimeoutMs == 0:
+		log.Printf("   HTTP Idle Conn Timeout: disabled (idle connections are never closed)")
+	default:
+		log.Printf("   HTTP Idle Conn Timeout: %dms", config.HTTPIdleConnTimeoutMs)
+	}
+	if config.HTTPMaxIdleConnsPerHost > 0 {
+		log.Printf("   HTTP Max Idle Conns Per Host: %d", config.HTTPMaxIdleConnsPerHost)
+	} else {
+		log.Printf("   HTTP Max Idle Conns Per Host: SDK default")
+	}
+}
+
+// maxThreadsPerProc is the worker-to-GOMAXPROCS ratio above which pacing accuracy is suspect
+const maxThreadsPerProc = 256
+
+// warnOnSchedulingPressure warns when there are far more workers than the runtime can run in parallel
+func warnOnSchedulingPressure(threads int) {
+	procs := runtime.GOMAXPROCS(0)
+	if threads > procs*maxThreadsPerProc {
+		logWarnf("%d threads on GOMAXPROCS=%d (%d per proc); goroutine scheduling may delay request pacing. "+
+			"Check the scheduling latency in the summary or spread load across more client processes.",
+			threads, procs, threads/procs)
+	}
+}
@@ -0,0 +1,42 @@
+package benchmark
+
+import "runtime"
+
+// RuntimeStats is a snapshot of the client process's heap, garbage collector and
+// goroutines, to tell client GC pauses apart from server latency
+type RuntimeStats struct {
+	HeapAllocBytes  uint64 `json:"heap_alloc_bytes"`
+	HeapSysBytes    uint64 `json:"heap_sys_bytes"`
+	TotalAllocBytes uint64 `json:"total_alloc_bytes"`
+	NumGC           uint32 `json:"num_gc"`
+	// GCPauseMaxMs covers the most recent 256 collections, which the runtime keeps
+	GCPauseTotalMs float64 `json:"gc_pause_total_ms"`
+	GCPauseMaxMs   float64 `json:"gc_pause_max_ms"`
+	GCCPUFraction  float64 `json:"gc_cpu_fraction"`
+	Goroutines     int     `json:"goroutines"`
+}
+
+// runtimeStats reads the current runtime statistics. Counters cover the whole
+// process lifetime, including warmup.
+func runtimeStats() RuntimeStats {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	var maxPause uint64
+	for _, pause := range mem.PauseNs {
+		if pause > maxPause {
+			maxPause = pause
+		}
+	}
+
+	return RuntimeStats{
+		HeapAllocBytes:  mem.HeapAlloc,
+		HeapSysBytes:    mem.HeapSys,
+		TotalAllocBytes: mem.TotalAlloc,
+		NumGC:           mem.NumGC,
+		GCPauseTotalMs:  float64(mem.PauseTotalNs) / 1_000_000.0,
+		GCPauseMaxMs:    float64(maxPause) / 1_000_000.0,
+		GCCPUFraction:   mem.GCCPUFraction,
+		Goroutines:      runtime.NumGoroutine(),
+	}
+}
@@ -0,0 +1,74 @@
+package benchmark
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+)
+
+// SamplingMetricsWriter wraps a MetricsWriter and forwards only a random fraction
+// of results to it. The decision is made before the result is queued, so skipped
+// results never occupy the wrapped writer's queue.
+type SamplingMetricsWriter struct {
+	inner MetricsWriter
+	rate  float64
+
+	mu  sync.Mutex
+	rng *rand.Rand
+
+	skipped int64
+}
+
+// NewSamplingMetricsWriter wraps inner, keeping each result with probability rate
+func NewSamplingMetricsWriter(inner MetricsWriter, rate float64, rng *rand.Rand) *SamplingMetricsWriter {
+	return &SamplingMetricsWriter{
+		inner: inner,
+		rate:  rate,
+		rng:   rng,
+	}
+}
+
+// Start starts the wrapped writer
+func (w *SamplingMetricsWriter) Start(ctx context.Context) {
+	w.inner.Start(ctx)
+}
+
+// WriteResult forwards the result if it is selected for the sample
+func (w *SamplingMetricsWriter) WriteResult(metrics *QueryExecutionMetrics) {
+	w.mu.Lock()
+	keep := w.rng.Float64() < w.rate
+	w.mu.Unlock()
+
+	if !keep {
+		atomic.AddInt64(&w.skipped, 1)
+		return
+	}
+	w.inner.WriteResult(metrics)
+}
+
+// Wait waits for the wrapped writer to finish
+func (w *SamplingMetricsWriter) Wait() {
+	w.inner.Wait()
+}
+
+// GetWrittenCount returns the number of results the wrapped writer wrote
+func (w *SamplingMetricsWriter) GetWrittenCount() int64 {
+	return w.inner.GetWrittenCount()
+}
+
+// GetDroppedCount returns the number of results the wrapped writer dropped.
+// Results left out of the sample are counted by SkippedCount instead.
+func (w *SamplingMetricsWriter) GetDroppedCount() int64 {
+	return w.inner.GetDroppedCount()
+}
+
+// GetQueueSize returns the wrapped writer's queue depth
+func (w *SamplingMetricsWriter) GetQueueSize() int {
+	return w.inner.GetQueueSize()
+}
+
+// SkippedCount returns the number of results left out of the sample
+func (w *SamplingMetricsWriter) SkippedCount() int64 {
+	return atomic.LoadInt64(&w.skipped)
+}
@@ -0,0 +1,144 @@
+package benchmark
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"runtime/debug"
+	"strings"
+	"time"
+)
+
+// Supported SDK types
+const (
+	SDKTypeOperational = "operational"
+	SDKTypeEnterprise  = "enterprise"
+)
+
+// Healthcheck failure policies
+const (
+	HealthcheckPolicyFail = "fail"
+	HealthcheckPolicyWarn = "warn"
+)
+
+// Supported scan consistencies. Unset leaves the SDK default, which is not bounded
+// for both SDKs; the summary records that as ScanConsistencySDKDefault.
+const (
+	ScanConsistencyNotBounded  = "not_bounded"
+	ScanConsistencyRequestPlus = "request_plus"
+	ScanConsistencySDKDefault  = "sdk_default"
+)
+
+// sdkModules maps each SDK type to the Go module implementing it
+var sdkModules = map[string]string{
+	SDKTypeOperational: "github.com/couchbase/gocb/v2",
+	SDKTypeEnterprise:  "github.com/couchbase/gocbanalytics",
+}
+
+// ResultFormatJSON is the only result encoding either SDK can request and decode
+const ResultFormatJSON = "json"
+
+// AnalyticsSDKHandler defines the interface for SDK handlers
+type AnalyticsSDKHandler interface {
+	// ExecuteQuery runs one measured request; a zero timeout uses the handler's
+	// default. Cancelling ctx abandons the request, e.g. at shutdown.
+	ExecuteQuery(ctx context.Context, query, queryName string, sequenceNumber int64, timeout time.Duration, params QueryParameters) *QueryExecutionMetrics
+	FetchRows(query string) ([]json.RawMessage, error)
+	TraceQuery(query string, params QueryParameters, canary int64) *CanaryTrace
+	GetSDKType() string
+	Close() error
+}
+
+// ConnectionTiming is how long opening one cluster connection took before the
+// run. ConnectMs covers Connect through WaitUntilReady for the operational SDK and
+// creating the cluster for the enterprise SDK; ProbeMs is the enterprise SDK's
+// test query.
+type ConnectionTiming struct {
+	Cluster          string  `json:"cluster,omitempty"`
+	Connection       int     `json:"connection"`
+	ConnectMs        float64 `json:"connect_ms"`
+	ProbeMs          float64 `json:"probe_ms,omitempty"`
+	ConnectionTimeMs float64 `json:"connection_time_ms"`
+}
+
+// connectionTimer is implemented by handlers that time opening their connections
+type connectionTimer interface {
+	ConnectionTimings() []ConnectionTiming
+}
+
+// validateSDKType checks the configured SDK type, listing the valid ones on failure
+func validateSDKType(sdkType string) error {
+	if _, ok := sdkModules[sdkType]; !ok && sdkType != SDKTypeBoth {
+		return fmt.Errorf("unknown SDK type: %q (valid types: %s, %s, %s)", sdkType, SDKTypeOperational, SDKTypeEnterprise, SDKTypeBoth)
+	}
+	return nil
+}
+
+// sdkLibraryVersion reports the version of the SDK module linked into the binary,
+// following replace directives so local SDK checkouts are identified as such
+func sdkLibraryVersion(sdkType string) (string, error) {
+	path := sdkModules[sdkType]
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "", fmt.Errorf("build info unavailable, cannot confirm %s is linked", path)
+	}
+
+	for _, dep := range info.Deps {
+		if dep.Path != path {
+			continue
+		}
+		if dep.Replace != nil {
+			version := strings.TrimSpace(dep.Replace.Version)
+			if version == "" {
+				version = "local"
+			}
+			return fmt.Sprintf("%s => %s %s", path, dep.Replace.Path, version), nil
+		}
+		return fmt.Sprintf("%s %s", path, dep.Version), nil
+	}
+	return "", fmt.Errorf("%s is not linked into this binary", path)
+}
+
+// validateHealthcheckPolicy checks the configured healthcheck policy name
+func validateHealthcheckPolicy(policy string) error {
+	switch policy {
+	case HealthcheckPolicyFail, HealthcheckPolicyWarn:
+		return nil
+	default:
+		return fmt.Errorf("unknown healthcheck policy: %s (expected %s or %s)",
+			policy, HealthcheckPolicyFail, HealthcheckPolicyWarn)
+	}
+}
+
+// validateScanConsistency checks the configured scan consistency name; empty
+// keeps the SDK default
+func validateScanConsistency(consistency string) error {
+	switch consistency {
+	case "", ScanConsistencyNotBounded, ScanConsistencyRequestPlus:
+		return nil
+	default:
+		return fmt.Errorf("unknown scan consistency: %s (expected %s or %s)",
+			consistency, ScanConsistencyNotBounded, ScanConsistencyRequestPlus)
+	}
+}
+
+// applyHealthcheckPolicy returns err under the fail policy. Under the warn policy it
+// logs err and returns nil, leaving the real query to be the test of the connection.
+func applyHealthcheckPolicy(policy string, err error) error {
+	if policy != HealthcheckPolicyWarn {
+		return err
+	}
+	logWarnf("Healthcheck failed, continuing because BENCHMARK_HEALTHCHECK_POLICY=%s: %v", policy, err)
+	return nil
+}
+
+// validateResultFormat checks the configured result format against what the SDKs expose.
+// Neither gocb's AnalyticsOptions nor gocbanalytics' QueryOptions has a result encoding
+// setting, so anything other than JSON is rejected rather than silently ignored.
+func validateResultFormat(format string) error {
+	if format != ResultFormatJSON {
+		return fmt.Errorf("unsupported result format: %s (the %s and %s SDKs only return %s)",
+			format, SDKTypeOperational, SDKTypeEnterprise, ResultFormatJSON)
+	}
+	return nil
+}
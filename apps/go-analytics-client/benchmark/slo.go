@@ -0,0 +1,95 @@
+package benchmark
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SLO is a latency objective for one percentile
+type SLO struct {
+	Name       string
+	Percentile float64
+	Threshold  time.Duration
+}
+
+// SLOResult is the outcome of checking one SLO against the measured latencies
+type SLOResult struct {
+	SLO
+	ActualMs float64
+	Passed   bool
+}
+
+// ParseSLOs parses a comma-separated SLO set such as "p50:10ms,p99:100ms,p999:500ms".
+// Thresholds without a unit are milliseconds.
+func ParseSLOs(spec string) ([]SLO, error) {
+	if strings.TrimSpace(spec) == "" {
+		return nil, nil
+	}
+
+	var slos []SLO
+	for _, entry := range strings.Split(spec, ",") {
+		name, threshold, ok := strings.Cut(strings.TrimSpace(entry), ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid SLO %q (expected pNN:threshold)", entry)
+		}
+		name = strings.ToLower(strings.TrimSpace(name))
+
+		percentile, err := parsePercentileName(name)
+		if err != nil {
+			return nil, fmt.Errorf("invalid SLO %q: %w", entry, err)
+		}
+		limit, err := parseThreshold(strings.TrimSpace(threshold))
+		if err != nil {
+			return nil, fmt.Errorf("invalid SLO %q: %w", entry, err)
+		}
+
+		slos = append(slos, SLO{Name: name, Percentile: percentile, Threshold: limit})
+	}
+	return slos, nil
+}
+
+// parsePercentileName maps names like p50, p99, p999 and p99.9 to a percentile
+func parsePercentileName(name string) (float64, error) {
+	digits := strings.TrimPrefix(name, "p")
+	if digits == name || digits == "" {
+		return 0, fmt.Errorf("percentile must look like p50, p99 or p999")
+	}
+
+	// p999 means 99.9 and p9999 means 99.99; p100 is the maximum
+	if !strings.Contains(digits, ".") && len(digits) > 2 && digits != "100" {
+		digits = digits[:2] + "." + digits[2:]
+	}
+
+	percentile, err := strconv.ParseFloat(digits, 64)
+	if err != nil || percentile <= 0 || percentile > 100 {
+		return 0, fmt.Errorf("percentile must be between 0 and 100")
+	}
+	return percentile, nil
+}
+
+// parseThreshold parses a duration, treating bare numbers as milliseconds
+func parseThreshold(value string) (time.Duration, error) {
+	if ms, err := strconv.ParseFloat(value, 64); err == nil {
+		return time.Duration(ms * float64(time.Millisecond)), nil
+	}
+	return time.ParseDuration(value)
+}
+
+// EvaluateSLOs checks each SLO against the recorded latencies. With no recorded
+// requests every SLO fails.
+func EvaluateSLOs(slos []SLO, latencies *LatencyRecorder) []SLOResult {
+	count := latencies.Count()
+	results := make([]SLOResult, 0, len(slos))
+	for _, slo := range slos {
+		actual := latencies.Percentiles(slo.Percentile)[slo.Percentile]
+		thresholdMs := float64(slo.Threshold.Nanoseconds()) / 1_000_000.0
+		results = append(results, SLOResult{
+			SLO:      slo,
+			ActualMs: actual,
+			Passed:   count > 0 && actual <= thresholdMs,
+		})
+	}
+	return results
+}
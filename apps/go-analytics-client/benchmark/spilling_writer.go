@@ -0,0 +1,163 @@
+package benchmark
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// spillDrainInterval is how often spilled results are moved back into the queue
+const spillDrainInterval = 5 * time.Millisecond
+
+// SpillingMetricsWriter wraps a MetricsWriter and, when the wrapped writer's queue
+// is full, holds results in a bounded in-memory spill instead of dropping them.
+// Spilled results are fed back in order as the queue drains, so a burst only
+// costs memory. Results are dropped only once the spill is full as well.
+type SpillingMetricsWriter struct {
+	inner    MetricsWriter
+	capacity int
+	limit    int
+
+	mu        sync.Mutex
+	spill     []*QueryExecutionMetrics
+	peakSpill int
+
+	droppedCount int64
+	stopped      chan struct{}
+}
+
+// NewSpillingMetricsWriter wraps inner, whose queue holds capacity results, with
+// a spill of up to limit results
+func NewSpillingMetricsWriter(inner MetricsWriter, capacity, limit int) *SpillingMetricsWriter {
+	return &SpillingMetricsWriter{
+		inner:    inner,
+		capacity: capacity,
+		limit:    limit,
+		stopped:  make(chan struct{}),
+	}
+}
+
+// Start runs the wrapped writer and moves spilled results into its queue until
+// ctx is cancelled. The spill is emptied before the wrapped writer is stopped, so
+// nothing spilled is lost at shutdown, unless the wrapped writer already stopped
+// on its own (e.g. it could not open its output); the spill is then dropped.
+func (w *SpillingMetricsWriter) Start(ctx context.Context) {
+	defer close(w.stopped)
+
+	innerCtx, cancelInner := context.WithCancel(context.Background())
+	defer cancelInner()
+	innerDone := make(chan struct{})
+	go func() {
+		defer close(innerDone)
+		w.inner.Start(innerCtx)
+	}()
+
+	ticker := time.NewTicker(spillDrainInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			for w.drain() > 0 {
+				select {
+				case <-ticker.C:
+				case <-innerDone:
+					if dropped := w.dropSpill(); dropped > 0 {
+						logWarnf("Metrics writer stopped before the spill was written, dropping %d results", dropped)
+					}
+					return
+				}
+			}
+			cancelInner()
+			<-innerDone
+			return
+		case <-ticker.C:
+			w.drain()
+		}
+	}
+}
+
+// WriteResult passes the result to the wrapped writer while its queue has room
+// and nothing is spilled, and spills it otherwise
+func (w *SpillingMetricsWriter) WriteResult(metrics *QueryExecutionMetrics) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	// Only this writer feeds the queue and only under the lock, so the queue
+	// cannot fill up between the check and the write
+	if len(w.spill) == 0 && w.inner.GetQueueSize() < w.capacity {
+		w.inner.WriteResult(metrics)
+		return
+	}
+	if len(w.spill) >= w.limit {
+		atomic.AddInt64(&w.droppedCount, 1)
+		logWarnf("Metrics writer queue and spill full, dropping result")
+		return
+	}
+	w.spill = append(w.spill, metrics)
+	if len(w.spill) > w.peakSpill {
+		w.peakSpill = len(w.spill)
+	}
+}
+
+// drain moves as many spilled results into the queue as it has room for and
+// returns how many are still spilled
+func (w *SpillingMetricsWriter) drain() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	moved := 0
+	for moved < len(w.spill) && w.inner.GetQueueSize() < w.capacity {
+		w.inner.WriteResult(w.spill[moved])
+		w.spill[moved] = nil
+		moved++
+	}
+	w.spill = w.spill[moved:]
+	if len(w.spill) == 0 {
+		// Release the backing array a burst may have grown
+		w.spill = nil
+	}
+	return len(w.spill)
+}
+
+// dropSpill discards every spilled result, counting it as dropped, and returns
+// how many there were
+func (w *SpillingMetricsWriter) dropSpill() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	dropped := len(w.spill)
+	atomic.AddInt64(&w.droppedCount, int64(dropped))
+	w.spill = nil
+	return dropped
+}
+
+// PeakSpill returns the most results that were spilled at once
+func (w *SpillingMetricsWriter) PeakSpill() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.peakSpill
+}
+
+func (w *SpillingMetricsWriter) Wait() {
+	<-w.stopped
+}
+
+func (w *SpillingMetricsWriter) GetWrittenCount() int64 {
+	return w.inner.GetWrittenCount()
+}
+
+// GetDroppedCount returns the results dropped with the spill full, plus any the
+// wrapped writer dropped itself
+func (w *SpillingMetricsWriter) GetDroppedCount() int64 {
+	return atomic.LoadInt64(&w.droppedCount) + w.inner.GetDroppedCount()
+}
+
+// GetQueueSize returns the wrapped writer's queue depth plus the spilled results
+func (w *SpillingMetricsWriter) GetQueueSize() int {
+	w.mu.Lock()
+	spilled := len(w.spill)
+	w.mu.Unlock()
+	return w.inner.GetQueueSize() + spilled
+}
@@ -0,0 +1,243 @@
+package benchmark
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+const (
+	sqliteTableName     = "query_metrics"
+	sqliteBatchSize     = 500
+	sqliteFlushInterval = time.Second
+)
+
+// MetricsSQLiteWriter writes metrics to a SQLite database file
+type MetricsSQLiteWriter struct {
+	outputFile   string
+	resultChan   chan *QueryExecutionMetrics
+	writtenCount int64
+	droppedCount int64
+	done         chan struct{}
+	wg           sync.WaitGroup
+}
+
+// NewMetricsSQLiteWriter creates a new SQLite metrics writer
+func NewMetricsSQLiteWriter(outputFile string, bufferSize int) *MetricsSQLiteWriter {
+	return &MetricsSQLiteWriter{
+		outputFile: outputFile,
+		resultChan: make(chan *QueryExecutionMetrics, bufferSize),
+		done:       make(chan struct{}),
+	}
+}
+
+// WriteResult queues a result for writing
+func (w *MetricsSQLiteWriter) WriteResult(metrics *QueryExecutionMetrics) {
+	select {
+	case w.resultChan <- metrics:
+	case <-w.done:
+		atomic.AddInt64(&w.droppedCount, 1)
+		logWarnf("Attempted to write to closed metrics writer")
+	default:
+		atomic.AddInt64(&w.droppedCount, 1)
+		logWarnf("Metrics writer queue full, dropping result")
+	}
+}
+
+// Start begins the writer goroutine
+func (w *MetricsSQLiteWriter) Start(ctx context.Context) {
+	w.wg.Add(1)
+	defer w.wg.Done()
+
+	if err := os.MkdirAll(filepath.Dir(w.outputFile), 0755); err != nil {
+		logErrorf("Failed to create output directory: %v", err)
+		return
+	}
+
+	log.Printf("MetricsSQLiteWriter starting for file: %s", w.outputFile)
+
+	// Start from an empty database, matching the truncate semantics of the JSON writer
+	if err := os.Remove(w.outputFile); err != nil && !os.IsNotExist(err) {
+		logErrorf("Failed to remove existing output file: %v", err)
+		return
+	}
+
+	db, err := sql.Open("sqlite", w.outputFile)
+	if err != nil {
+		logErrorf("Failed to open output database: %v", err)
+		return
+	}
+	defer db.Close()
+
+	if err := createMetricsTable(db); err != nil {
+		logErrorf("Failed to create metrics table: %v", err)
+		return
+	}
+
+	insertSQL := metricsInsertSQL()
+	batch := make([]*QueryExecutionMetrics, 0, sqliteBatchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if err := insertMetricsBatch(db, insertSQL, batch); err != nil {
+			logErrorf("Failed to insert %d results: %v", len(batch), err)
+		} else {
+			count := atomic.AddInt64(&w.writtenCount, int64(len(batch)))
+			log.Printf("Wrote %d results (total %d)", len(batch), count)
+		}
+		batch = batch[:0]
+	}
+
+	ticker := time.NewTicker(sqliteFlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Printf("MetricsSQLiteWriter shutting down, draining remaining results...")
+			close(w.done)
+
+			drained := 0
+			for {
+				select {
+				case result := <-w.resultChan:
+					batch = append(batch, result)
+					drained++
+					if len(batch) >= sqliteBatchSize {
+						flush()
+					}
+				default:
+					flush()
+					log.Printf("MetricsSQLiteWriter completed. Total results written: %d (drained %d during shutdown)",
+						atomic.LoadInt64(&w.writtenCount), drained)
+					return
+				}
+			}
+
+		case result := <-w.resultChan:
+			batch = append(batch, result)
+			if len(batch) >= sqliteBatchSize {
+				flush()
+			}
+
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+func (w *MetricsSQLiteWriter) Wait() {
+	w.wg.Wait()
+}
+
+func (w *MetricsSQLiteWriter) GetWrittenCount() int64 {
+	return atomic.LoadInt64(&w.writtenCount)
+}
+
+// GetDroppedCount returns the number of results lost to a full queue or a closed writer
+func (w *MetricsSQLiteWriter) GetDroppedCount() int64 {
+	return atomic.LoadInt64(&w.droppedCount)
+}
+
+func (w *MetricsSQLiteWriter) GetQueueSize() int {
+	return len(w.resultChan)
+}
+
+// createMetricsTable creates the metrics table with one column per metrics field
+func createMetricsTable(db *sql.DB) error {
+	columns := make([]string, len(metricsColumns))
+	for i, column := range metricsColumns {
+		columns[i] = fmt.Sprintf("%s %s", column.Name, sqliteColumnType(column.Kind))
+	}
+
+	statements := []string{
+		fmt.Sprintf("CREATE TABLE %s (%s)", sqliteTableName, strings.Join(columns, ", ")),
+		fmt.Sprintf("CREATE INDEX idx_%s_sequence_number ON %s (sequence_number)", sqliteTableName, sqliteTableName),
+		fmt.Sprintf("CREATE INDEX idx_%s_query_name ON %s (query_name)", sqliteTableName, sqliteTableName),
+	}
+	for _, statement := range statements {
+		if _, err := db.Exec(statement); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// metricsInsertSQL builds the parameterized insert statement for a metrics row
+func metricsInsertSQL() string {
+	names := make([]string, len(metricsColumns))
+	placeholders := make([]string, len(metricsColumns))
+	for i, column := range metricsColumns {
+		names[i] = column.Name
+		placeholders[i] = "?"
+	}
+	return fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)",
+		sqliteTableName, strings.Join(names, ", "), strings.Join(placeholders, ", "))
+}
+
+// insertMetricsBatch inserts a batch of results in a single transaction
+func insertMetricsBatch(db *sql.DB, insertSQL string, batch []*QueryExecutionMetrics) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+
+	stmt, err := tx.Prepare(insertSQL)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	defer stmt.Close()
+
+	for _, metrics := range batch {
+		values := metricsValues(metrics)
+		for i, column := range metricsColumns {
+			values[i] = sqliteValue(column.Kind, values[i])
+		}
+		if _, err := stmt.Exec(values...); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// sqliteColumnType maps a metrics field kind to its SQLite storage class
+func sqliteColumnType(kind reflect.Kind) string {
+	switch kind {
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "INTEGER"
+	case reflect.Float32, reflect.Float64:
+		return "REAL"
+	default:
+		return "TEXT"
+	}
+}
+
+// sqliteValue converts composite field values to JSON text so they can be bound
+func sqliteValue(kind reflect.Kind, value interface{}) interface{} {
+	switch kind {
+	case reflect.Map, reflect.Slice, reflect.Struct, reflect.Ptr:
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return nil
+		}
+		return string(encoded)
+	default:
+		return value
+	}
+}
@@ -0,0 +1,306 @@
+package benchmark
+
+import (
+	"math"
+	"math/bits"
+	"sort"
+	"sync"
+	"time"
+)
+
+// SizeLatencyStats accumulates response size and latency pairs to correlate them
+type SizeLatencyStats struct {
+	mu    sync.Mutex
+	n     int64
+	sumX  float64
+	sumY  float64
+	sumXX float64
+	sumYY float64
+	sumXY float64
+}
+
+// Add records one sampled request
+func (s *SizeLatencyStats) Add(responseBytes int64, latencyMs float64) {
+	x := float64(responseBytes)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.n++
+	s.sumX += x
+	s.sumY += latencyMs
+	s.sumXX += x * x
+	s.sumYY += latencyMs * latencyMs
+	s.sumXY += x * latencyMs
+}
+
+// Count returns the number of sampled requests
+func (s *SizeLatencyStats) Count() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.n
+}
+
+// MeanBytes returns the average sampled response size
+func (s *SizeLatencyStats) MeanBytes() float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.n == 0 {
+		return 0
+	}
+	return s.sumX / float64(s.n)
+}
+
+// Correlation returns the Pearson correlation between response size and latency.
+// It reports false when there are too few samples or either variable is constant.
+func (s *SizeLatencyStats) Correlation() (float64, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.n < 2 {
+		return 0, false
+	}
+
+	n := float64(s.n)
+	covariance := n*s.sumXY - s.sumX*s.sumY
+	varianceX := n*s.sumXX - s.sumX*s.sumX
+	varianceY := n*s.sumYY - s.sumY*s.sumY
+	if varianceX <= 0 || varianceY <= 0 {
+		return 0, false
+	}
+
+	return covariance / math.Sqrt(varianceX*varianceY), true
+}
+
+// SchedulingLatencyStats tracks how late workers wake up relative to their intended start time
+type SchedulingLatencyStats struct {
+	mu    sync.Mutex
+	count int64
+	total time.Duration
+	max   time.Duration
+}
+
+// Add records the delay between an intended and actual loop iteration start
+func (s *SchedulingLatencyStats) Add(delay time.Duration) {
+	if delay < 0 {
+		delay = 0
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.count++
+	s.total += delay
+	if delay > s.max {
+		s.max = delay
+	}
+}
+
+// Snapshot returns the number of wakeups and the average and maximum delay
+func (s *SchedulingLatencyStats) Snapshot() (int64, time.Duration, time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.count == 0 {
+		return 0, 0, 0
+	}
+	return s.count, s.total / time.Duration(s.count), s.max
+}
+
+// ReconnectStats compares requests issued right after a forced reconnect with the rest
+type ReconnectStats struct {
+	mu               sync.Mutex
+	reconnects       int64
+	totalReconnectMs float64
+	afterLatencyMs   float64
+	otherCount       int64
+	otherLatencyMs   float64
+}
+
+// Add records one result from a connection-churn run
+func (s *ReconnectStats) Add(result *QueryExecutionMetrics) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if result.AfterReconnect {
+		s.reconnects++
+		s.totalReconnectMs += result.ReconnectMs
+		s.afterLatencyMs += result.DurationMs
+		return
+	}
+	s.otherCount++
+	s.otherLatencyMs += result.DurationMs
+}
+
+// Snapshot returns the reconnect count, average reconnect time, and the average latency
+// of the first request after a reconnect versus all other requests
+func (s *ReconnectStats) Snapshot() (int64, float64, float64, float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var avgReconnectMs, avgAfterMs, avgOtherMs float64
+	if s.reconnects > 0 {
+		avgReconnectMs = s.totalReconnectMs / float64(s.reconnects)
+		avgAfterMs = s.afterLatencyMs / float64(s.reconnects)
+	}
+	if s.otherCount > 0 {
+		avgOtherMs = s.otherLatencyMs / float64(s.otherCount)
+	}
+	return s.reconnects, avgReconnectMs, avgAfterMs, avgOtherMs
+}
+
+// AllocStats accumulates client heap allocations measured around sampled requests
+type AllocStats struct {
+	mu     sync.Mutex
+	count  int64
+	bytes  uint64
+	allocs uint64
+}
+
+// Add records the allocations measured around one request
+func (s *AllocStats) Add(bytes, allocs uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.count++
+	s.bytes += bytes
+	s.allocs += allocs
+}
+
+// Snapshot returns the sample count and the average bytes and allocations per request
+func (s *AllocStats) Snapshot() (int64, float64, float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.count == 0 {
+		return 0, 0, 0
+	}
+	n := float64(s.count)
+	return s.count, float64(s.bytes) / n, float64(s.allocs) / n
+}
+
+// percentileOfSorted returns the nearest-rank percentile p (0-100) of an ascending slice
+func percentileOfSorted(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p / 100.0 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	if rank > len(sorted) {
+		rank = len(sorted)
+	}
+	return sorted[rank-1]
+}
+
+// summaryPercentiles are the latency percentiles reported at the end of a run;
+// 100 is the maximum
+var summaryPercentiles = []float64{50, 90, 95, 99, 100}
+
+// PercentileCalculator computes latency percentiles from successful requests,
+// or from all requests when IncludeFailures is set
+type PercentileCalculator struct {
+	IncludeFailures bool
+}
+
+// Calculate returns each requested percentile (0-100) in milliseconds. Every
+// percentile of an empty input is zero.
+func (c PercentileCalculator) Calculate(success, failure *LatencyRecorder, percentiles ...float64) map[float64]float64 {
+	if c.IncludeFailures {
+		return success.Merged(failure).Percentiles(percentiles...)
+	}
+	return success.Percentiles(percentiles...)
+}
+
+// RowCountStats counts how often each row count was returned
+type RowCountStats struct {
+	mu     sync.Mutex
+	counts map[int]int64
+	total  int64
+}
+
+// NewRowCountStats creates an empty row count distribution
+func NewRowCountStats() *RowCountStats {
+	return &RowCountStats{counts: make(map[int]int64)}
+}
+
+// Add records the row count of one request
+func (s *RowCountStats) Add(rowCount int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.counts[rowCount]++
+	s.total++
+}
+
+// RowCountBucket is one power-of-two histogram bucket of row counts, inclusive bounds
+type RowCountBucket struct {
+	Lower int
+	Upper int
+	Count int64
+}
+
+// Percentiles returns the nearest-rank row count at each requested percentile
+func (s *RowCountStats) Percentiles(percentiles ...float64) []int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	results := make([]int, len(percentiles))
+	if s.total == 0 {
+		return results
+	}
+
+	values := s.sortedValues()
+	for i, p := range percentiles {
+		rank := int64(math.Ceil(p / 100.0 * float64(s.total)))
+		if rank < 1 {
+			rank = 1
+		}
+		var seen int64
+		for _, value := range values {
+			seen += s.counts[value]
+			if seen >= rank {
+				results[i] = value
+				break
+			}
+		}
+	}
+	return results
+}
+
+// Histogram groups row counts into buckets 0, 1, 2-3, 4-7, ... skipping empty ones
+func (s *RowCountStats) Histogram() []RowCountBucket {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var buckets []RowCountBucket
+	for _, value := range s.sortedValues() {
+		lower, upper := 0, 0
+		if value > 0 {
+			lower = 1 << (bits.Len(uint(value)) - 1)
+			upper = lower*2 - 1
+		}
+		if n := len(buckets); n > 0 && buckets[n-1].Lower == lower {
+			buckets[n-1].Count += s.counts[value]
+			continue
+		}
+		buckets = append(buckets, RowCountBucket{Lower: lower, Upper: upper, Count: s.counts[value]})
+	}
+	return buckets
+}
+
+// Total returns the number of recorded requests
+func (s *RowCountStats) Total() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.total
+}
+
+func (s *RowCountStats) sortedValues() []int {
+	values := make([]int, 0, len(s.counts))
+	for value := range s.counts {
+		values = append(values, value)
+	}
+	sort.Ints(values)
+	return values
+}
@@ -0,0 +1,113 @@
+package benchmark
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// statsServerShutdownTimeout bounds how long in-flight stats requests may delay shutdown
+const statsServerShutdownTimeout = 2 * time.Second
+
+// IntervalStats are the latency percentiles of the last completed progress interval
+type IntervalStats struct {
+	Completed int64   `json:"completed"`
+	P50Ms     float64 `json:"p50_ms"`
+	P95Ms     float64 `json:"p95_ms"`
+	P99Ms     float64 `json:"p99_ms"`
+}
+
+// LiveStats is the /stats response
+type LiveStats struct {
+	SDKType     string         `json:"sdk_type"`
+	ElapsedS    float64        `json:"elapsed_s"`
+	Requests    int64          `json:"requests"`
+	Successes   int64          `json:"successes"`
+	SuccessRate float64        `json:"success_rate"`
+	RPS         float64        `json:"rps"`
+	Interval    *IntervalStats `json:"interval,omitempty"`
+}
+
+// StatsServer serves live statistics over HTTP while the test runs. It reads the
+// same atomic counters as the progress monitor, and the interval percentiles the
+// monitor has already computed, so requests never touch the workers' hot path.
+type StatsServer struct {
+	server       *http.Server
+	sdkType      string
+	startTime    time.Time
+	requestCount *int64
+	successCount *int64
+	interval     atomic.Pointer[IntervalStats]
+}
+
+// NewStatsServer creates a server on port reporting the given counters
+func NewStatsServer(port int, sdkType string, startTime time.Time, requestCount, successCount *int64) *StatsServer {
+	s := &StatsServer{
+		sdkType:      sdkType,
+		startTime:    startTime,
+		requestCount: requestCount,
+		successCount: successCount,
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/stats", s.handleStats)
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte("ok\n"))
+	})
+	s.server = &http.Server{Addr: fmt.Sprintf(":%d", port), Handler: mux}
+	return s
+}
+
+// Start serves in the background until Close. A port that cannot be bound is
+// logged and the run continues without the server.
+func (s *StatsServer) Start() {
+	go func() {
+		if err := s.server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logWarnf("Stats server stopped: %v", err)
+		}
+	}()
+	log.Printf("📡 Live stats at http://localhost%s/stats", s.server.Addr)
+}
+
+// PublishInterval replaces the interval percentiles /stats reports. It is safe
+// to call on a nil server.
+func (s *StatsServer) PublishInterval(stats IntervalStats) {
+	if s == nil {
+		return
+	}
+	s.interval.Store(&stats)
+}
+
+func (s *StatsServer) handleStats(w http.ResponseWriter, _ *http.Request) {
+	elapsed := time.Since(s.startTime).Seconds()
+	requests := atomic.LoadInt64(s.requestCount)
+	successes := atomic.LoadInt64(s.successCount)
+
+	stats := LiveStats{
+		SDKType:   s.sdkType,
+		ElapsedS:  elapsed,
+		Requests:  requests,
+		Successes: successes,
+		Interval:  s.interval.Load(),
+	}
+	if elapsed > 0 {
+		stats.RPS = float64(successes) / elapsed
+	}
+	if requests > 0 {
+		stats.SuccessRate = float64(successes) * 100.0 / float64(requests)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(stats)
+}
+
+// Close stops the server, letting in-flight requests finish briefly
+func (s *StatsServer) Close() {
+	ctx, cancel := context.WithTimeout(context.Background(), statsServerShutdownTimeout)
+	defer cancel()
+	s.server.Shutdown(ctx)
+}
@@ -0,0 +1,120 @@
+package benchmark
+
+import (
+	"encoding/json"
+	"os"
+)
+
+// LatencyPercentiles are the end-of-run latency percentiles in milliseconds
+type LatencyPercentiles struct {
+	P50Ms float64 `json:"p50_ms"`
+	P90Ms float64 `json:"p90_ms"`
+	P95Ms float64 `json:"p95_ms"`
+	P99Ms float64 `json:"p99_ms"`
+	MaxMs float64 `json:"max_ms"`
+}
+
+// NewLatencyPercentiles picks the summary percentiles out of a PercentileCalculator result
+func NewLatencyPercentiles(p map[float64]float64) LatencyPercentiles {
+	return LatencyPercentiles{
+		P50Ms: p[50],
+		P90Ms: p[90],
+		P95Ms: p[95],
+		P99Ms: p[99],
+		MaxMs: p[100],
+	}
+}
+
+// QuerySummary is the outcome and latency of one query name in the mix, or of
+// one cluster in a multi-cluster run
+type QuerySummary struct {
+	Name          string             `json:"name"`
+	TotalRequests int64              `json:"total_requests"`
+	Successes     int64              `json:"successes"`
+	Failures      int64              `json:"failures"`
+	SuccessRate   float64            `json:"success_rate"`
+	Latency       LatencyPercentiles `json:"latency"`
+}
+
+// SLOSummary is the outcome of one latency SLO
+type SLOSummary struct {
+	Name        string  `json:"name"`
+	ThresholdMs float64 `json:"threshold_ms"`
+	ObservedMs  float64 `json:"observed_ms"`
+	Passed      bool    `json:"passed"`
+}
+
+// NewSLOSummaries converts SLO results for the summary report
+func NewSLOSummaries(results []SLOResult) []SLOSummary {
+	summaries := make([]SLOSummary, 0, len(results))
+	for _, result := range results {
+		summaries = append(summaries, SLOSummary{
+			Name:        result.Name,
+			ThresholdMs: float64(result.Threshold.Nanoseconds()) / 1_000_000.0,
+			ObservedMs:  result.ActualMs,
+			Passed:      result.Passed,
+		})
+	}
+	return summaries
+}
+
+// SummaryReport is the machine-readable aggregate of a run, written next to the raw metrics
+type SummaryReport struct {
+	SDKType         string             `json:"sdk_type"`
+	QueryName       string             `json:"query_name"`
+	RunTimestamp    string             `json:"run_timestamp"`
+	RandomSeed      int64              `json:"random_seed"`
+	StartTimeMs     int64              `json:"start_time_ms"`
+	TestDurationMs  float64            `json:"test_duration_ms"`
+	TotalRequests   int64              `json:"total_requests"`
+	Successes       int64              `json:"successes"`
+	Failures        int64              `json:"failures"`
+	SuccessRate     float64            `json:"success_rate"`
+	ThroughputRPS   float64            `json:"throughput_rps"`
+	GoodputRPS      float64            `json:"goodput_rps"`
+	LatencyIncludes string             `json:"latency_includes"`
+	CacheBusting    bool               `json:"cache_busting,omitempty"`
+	ScanConsistency string             `json:"scan_consistency"`
+	Latency         LatencyPercentiles `json:"latency"`
+	// LatencyCorrected is set when coordinated omission correction is enabled.
+	// LatencyFirstRow covers successful requests that returned at least one row.
+	// LatencyServer is the server-reported elapsed time of successful requests.
+	LatencyCorrected *LatencyPercentiles `json:"latency_corrected,omitempty"`
+	LatencyFirstRow  *LatencyPercentiles `json:"latency_first_row,omitempty"`
+	LatencyServer    *LatencyPercentiles `json:"latency_server,omitempty"`
+	Queries          []QuerySummary      `json:"queries"`
+	Clusters         []QuerySummary      `json:"clusters,omitempty"`
+	Workers          []WorkerSummary     `json:"workers,omitempty"`
+	ErrorCategories  map[string]int64    `json:"error_categories,omitempty"`
+	Outliers         OutlierSummary      `json:"outliers"`
+	SLOs             []SLOSummary        `json:"slos,omitempty"`
+	ResultsWritten   int64               `json:"results_written"`
+	ResultsDropped   int64               `json:"results_dropped"`
+	ResultsSkipped   int64               `json:"results_skipped,omitempty"`
+	Canceled         int64               `json:"canceled_at_shutdown,omitempty"`
+	Cooldown         int64               `json:"completed_in_cooldown,omitempty"`
+	Shed             int64               `json:"shed,omitempty"`
+	RawOutputFile    string              `json:"raw_output_file"`
+	Aborted          string              `json:"aborted,omitempty"`
+	// ConnectionTimeMs is the time spent opening connections before the run,
+	// summed over Connections
+	ConnectionTimeMs float64            `json:"connection_time_ms"`
+	Connections      []ConnectionTiming `json:"connections,omitempty"`
+	// HealthReconnects lists the connections the health monitor replaced
+	HealthReconnects []HealthReconnectEvent `json:"health_reconnects,omitempty"`
+	Runtime          RuntimeStats           `json:"runtime"`
+}
+
+// summaryReportPath returns where the summary for outputFile is written
+func summaryReportPath(outputFile string) string {
+	return artifactBase(outputFile) + ".summary.json"
+}
+
+// WriteFile writes the report as indented JSON
+func (r *SummaryReport) WriteFile(path string) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
@@ -0,0 +1,314 @@
+package benchmark
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// sweepSaturationGain is the goodput gain over every lighter point below which
+// more offered load counts as no longer scaling
+const sweepSaturationGain = 0.05
+
+// Sweep is the matrix of thread counts and request intervals a sweep runs. An
+// axis left unset holds just the configured value.
+type Sweep struct {
+	Threads            []int
+	RequestIntervalsMs []int64
+	Pause              time.Duration
+}
+
+// SweepPoint is the outcome of one combination of a sweep
+type SweepPoint struct {
+	Threads           int                `json:"threads"`
+	RequestIntervalMs int64              `json:"request_interval_ms"`
+	OfferedRPS        float64            `json:"offered_rps"`
+	ThroughputRPS     float64            `json:"throughput_rps"`
+	GoodputRPS        float64            `json:"goodput_rps"`
+	SuccessRate       float64            `json:"success_rate"`
+	Latency           LatencyPercentiles `json:"latency"`
+	Saturated         bool               `json:"saturated"`
+	SummaryFile       string             `json:"summary_file"`
+	Error             string             `json:"error,omitempty"`
+	// completed is set when the run produced a summary, even one failing its SLOs
+	completed bool
+}
+
+// SweepReport is the comparison table of a sweep. Saturation is the heaviest
+// point whose goodput still scaled with the offered load, if any completed.
+type SweepReport struct {
+	Points     []SweepPoint `json:"points"`
+	Saturation *SweepPoint  `json:"saturation,omitempty"`
+}
+
+// ParseSweep reads the sweep axes from config, returning nil when neither is set
+func ParseSweep(config Configuration) (*Sweep, error) {
+	if config.SweepThreads == "" && config.SweepRequestIntervalsMs == "" {
+		return nil, nil
+	}
+	sweep := &Sweep{
+		Threads:            []int{config.Threads},
+		RequestIntervalsMs: []int64{config.RequestIntervalMs},
+		Pause:              time.Duration(config.SweepPauseMs) * time.Millisecond,
+	}
+	if config.SweepPauseMs < 0 {
+		return nil, fmt.Errorf("BENCHMARK_SWEEP_PAUSE_MS must not be negative")
+	}
+	if config.SweepThreads != "" {
+		values, err := parseSweepAxis(config.SweepThreads, 1)
+		if err != nil {
+			return nil, fmt.Errorf("invalid BENCHMARK_SWEEP_THREADS: %w", err)
+		}
+		sweep.Threads = sweep.Threads[:0]
+		for _, v := range values {
+			sweep.Threads = append(sweep.Threads, int(v))
+		}
+	}
+	if config.SweepRequestIntervalsMs != "" {
+		values, err := parseSweepAxis(config.SweepRequestIntervalsMs, 0)
+		if err != nil {
+			return nil, fmt.Errorf("invalid BENCHMARK_SWEEP_REQUEST_INTERVALS_MS: %w", err)
+		}
+		sweep.RequestIntervalsMs = values
+	}
+	return sweep, nil
+}
+
+// parseSweepAxis parses a comma-separated list of integers of at least min
+func parseSweepAxis(spec string, min int64) ([]int64, error) {
+	var values []int64
+	for _, entry := range strings.Split(spec, ",") {
+		v, err := strconv.ParseInt(strings.TrimSpace(entry), 10, 32)
+		if err != nil || v < min {
+			return nil, fmt.Errorf("%q is not an integer of at least %d", entry, min)
+		}
+		values = append(values, v)
+	}
+	return values, nil
+}
+
+// sweepPointPath suffixes the output file with the combination before the
+// extension, e.g. results.jsonl becomes results.t8-i100.jsonl
+func sweepPointPath(outputFile string, threads int, intervalMs int64) string {
+	ext := filepath.Ext(outputFile)
+	return fmt.Sprintf("%s.t%d-i%d%s", strings.TrimSuffix(outputFile, ext), threads, intervalMs, ext)
+}
+
+// sweepReportPath returns where the sweep table for outputFile is written
+func sweepReportPath(outputFile, ext string) string {
+	return outputFile + ".sweep." + ext
+}
+
+// sweepPointConfig is config with one combination applied and its own output files
+func sweepPointConfig(config Configuration, threads int, intervalMs int64) Configuration {
+	point := config
+	point.SweepThreads, point.SweepRequestIntervalsMs = "", ""
+	point.Threads = threads
+	point.RequestIntervalMs = intervalMs
+	point.OutputFile = sweepPointPath(config.OutputFile, threads, intervalMs)
+	if config.WarmupOutputFile != "" {
+		point.WarmupOutputFile = sweepPointPath(config.WarmupOutputFile, threads, intervalMs)
+	}
+	if config.VerificationOutputFile != "" {
+		point.VerificationOutputFile = sweepPointPath(config.VerificationOutputFile, threads, intervalMs)
+	}
+	return point
+}
+
+// RunSweep runs the benchmark once per combination of the configured sweep axes,
+// threads outermost, pausing between runs so one run's backlog does not bleed into
+// the next. A failed run is recorded and the sweep moves on; cancelling ctx stops
+// it. The comparison table is logged and written as <output>.sweep.json and
+// <output>.sweep.csv.
+func RunSweep(ctx context.Context, config Configuration) (*SweepReport, error) {
+	sweep, err := ParseSweep(config)
+	if err != nil {
+		return nil, err
+	}
+	if sweep == nil {
+		return nil, fmt.Errorf("no sweep configured: set BENCHMARK_SWEEP_THREADS or BENCHMARK_SWEEP_REQUEST_INTERVALS_MS")
+	}
+	if config.SDKType == SDKTypeBoth || isStdoutOutput(config.OutputFile) {
+		return nil, fmt.Errorf("a sweep needs a single SDK type and an output file")
+	}
+
+	// Validate every combination up front so a bad one does not fail the sweep halfway
+	var runners []*SimpleAnalyticsRunner
+	for _, threads := range sweep.Threads {
+		for _, intervalMs := range sweep.RequestIntervalsMs {
+			runner, err := NewSimpleAnalyticsRunner(sweepPointConfig(config, threads, intervalMs))
+			if err != nil {
+				return nil, fmt.Errorf("sweep point %d threads x %dms: %w", threads, intervalMs, err)
+			}
+			runners = append(runners, runner)
+		}
+	}
+
+	report := &SweepReport{}
+	for i, runner := range runners {
+		if i > 0 && !sleepContext(ctx, sweep.Pause) {
+			break
+		}
+		threads, intervalMs := runner.config.Threads, runner.config.RequestIntervalMs
+		log.Printf("🧭 Sweep run %d/%d: %d threads, %dms request interval", i+1, len(runners), threads, intervalMs)
+		runner.LogConfiguration()
+
+		summary, err := runner.Run(ctx)
+		point := SweepPoint{
+			Threads:           threads,
+			RequestIntervalMs: intervalMs,
+			OfferedRPS:        offeredRPS(runner.config),
+			SummaryFile:       summaryReportPath(runner.config.OutputFile),
+		}
+		if summary != nil {
+			point.completed = true
+			point.ThroughputRPS = summary.ThroughputRPS
+			point.GoodputRPS = summary.GoodputRPS
+			point.SuccessRate = summary.SuccessRate
+			point.Latency = summary.Latency
+		}
+		if err != nil {
+			logWarnf("Sweep run %d/%d failed: %v", i+1, len(runners), err)
+			point.Error = err.Error()
+		}
+		report.Points = append(report.Points, point)
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	report.markSaturation()
+	report.log()
+	if err := report.writeFiles(config.OutputFile); err != nil {
+		return report, fmt.Errorf("failed to write sweep report: %w", err)
+	}
+	if ctx.Err() != nil {
+		return report, fmt.Errorf("sweep interrupted after %d of %d runs", len(report.Points), len(runners))
+	}
+	return report, nil
+}
+
+// offeredRPS is the load a combination asks for: the target throughput or
+// per-worker rate when set, otherwise every worker lane once per request
+// interval. Unpaced runs offer unbounded load, reported as 0.
+func offeredRPS(config Configuration) float64 {
+	if config.TargetRPS > 0 {
+		return float64(config.TargetRPS)
+	}
+	if config.PerWorkerRPS > 0 {
+		return float64(config.PerWorkerRPS * config.Threads)
+	}
+	if config.RequestIntervalMs <= 0 {
+		return 0
+	}
+	return float64(config.Threads*config.PipelineDepth) * 1000.0 / float64(config.RequestIntervalMs)
+}
+
+// markSaturation walks the points that produced a summary from lightest to heaviest offered
+// load and marks each one whose goodput is not at least sweepSaturationGain above
+// the best seen so far. The last point before the first saturated one is the
+// saturation point.
+func (s *SweepReport) markSaturation() {
+	var order []int
+	for i, point := range s.Points {
+		if point.completed {
+			order = append(order, i)
+		}
+	}
+	load := func(p SweepPoint) float64 {
+		if p.OfferedRPS == 0 {
+			return math.Inf(1)
+		}
+		return p.OfferedRPS
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		pa, pb := s.Points[order[a]], s.Points[order[b]]
+		if load(pa) != load(pb) {
+			return load(pa) < load(pb)
+		}
+		return pa.Threads < pb.Threads
+	})
+
+	best := -1
+	for _, i := range order {
+		point := &s.Points[i]
+		if best >= 0 && point.GoodputRPS < s.Points[best].GoodputRPS*(1+sweepSaturationGain) {
+			point.Saturated = true
+			if s.Saturation == nil {
+				saturation := s.Points[best]
+				s.Saturation = &saturation
+			}
+			continue
+		}
+		if best < 0 || point.GoodputRPS > s.Points[best].GoodputRPS {
+			best = i
+		}
+	}
+}
+
+// log prints the sweep as a table, flagging points past the saturation point
+func (s *SweepReport) log() {
+	log.Printf("📊 Sweep Results:")
+	log.Printf("   %7s %11s %10s %10s %10s %8s %9s %9s %9s  %s",
+		"Threads", "Interval", "Offered", "RPS", "Goodput", "Success", "p50", "p95", "p99", "")
+	for _, point := range s.Points {
+		note := ""
+		switch {
+		case !point.completed:
+			note = "failed"
+		case point.Saturated:
+			note = "saturated"
+		}
+		if point.completed && point.Error != "" {
+			note = strings.TrimSpace(note + " (run failed its checks)")
+		}
+		log.Printf("   %7d %9dms %10.2f %10.2f %10.2f %7.2f%% %7.2fms %7.2fms %7.2fms  %s",
+			point.Threads, point.RequestIntervalMs, point.OfferedRPS, point.ThroughputRPS, point.GoodputRPS,
+			point.SuccessRate, point.Latency.P50Ms, point.Latency.P95Ms, point.Latency.P99Ms, note)
+	}
+	if s.Saturation != nil {
+		log.Printf("   Saturation: goodput stops scaling past %d threads at %dms (%.2f RPS, p99 %.2fms)",
+			s.Saturation.Threads, s.Saturation.RequestIntervalMs, s.Saturation.GoodputRPS, s.Saturation.Latency.P99Ms)
+	} else {
+		log.Printf("   Saturation: not reached; goodput scaled with every step")
+	}
+}
+
+// writeFiles writes the sweep as indented JSON and as a CSV table
+func (s *SweepReport) writeFiles(outputFile string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(sweepReportPath(outputFile, "json"), append(data, '\n'), 0644); err != nil {
+		return err
+	}
+
+	file, err := os.Create(sweepReportPath(outputFile, "csv"))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	w := csv.NewWriter(file)
+	w.Write([]string{"threads", "request_interval_ms", "offered_rps", "throughput_rps", "goodput_rps", "success_rate",
+		"p50_ms", "p90_ms", "p95_ms", "p99_ms", "max_ms", "saturated", "summary_file", "error"})
+	f := func(v float64) string { return strconv.FormatFloat(v, 'f', 3, 64) }
+	for _, p := range s.Points {
+		w.Write([]string{strconv.Itoa(p.Threads), strconv.FormatInt(p.RequestIntervalMs, 10), f(p.OfferedRPS),
+			f(p.ThroughputRPS), f(p.GoodputRPS), f(p.SuccessRate), f(p.Latency.P50Ms), f(p.Latency.P90Ms),
+			f(p.Latency.P95Ms), f(p.Latency.P99Ms), f(p.Latency.MaxMs), strconv.FormatBool(p.Saturated),
+			p.SummaryFile, p.Error})
+	}
+	w.Flush()
+	return w.Error()
+}
@@ -0,0 +1,56 @@
+package benchmark
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// Supported think-time distributions
+const (
+	ThinkTimeFixed       = "fixed"
+	ThinkTimeUniform     = "uniform"
+	ThinkTimeExponential = "exponential"
+)
+
+// ThinkTimeSampler draws the pause a worker takes after each completed request
+type ThinkTimeSampler struct {
+	meanMs       int64
+	distribution string
+	rng          *rand.Rand
+}
+
+// NewThinkTimeSampler creates a sampler for the given distribution and mean
+func NewThinkTimeSampler(distribution string, meanMs int64, rng *rand.Rand) *ThinkTimeSampler {
+	return &ThinkTimeSampler{
+		meanMs:       meanMs,
+		distribution: distribution,
+		rng:          rng,
+	}
+}
+
+// Next returns the next think time to apply
+func (s *ThinkTimeSampler) Next() time.Duration {
+	mean := float64(s.meanMs) * float64(time.Millisecond)
+
+	switch s.distribution {
+	case ThinkTimeUniform:
+		// Uniform over [0, 2*mean] so the average matches the configured value
+		return time.Duration(s.rng.Float64() * 2 * mean)
+	case ThinkTimeExponential:
+		return time.Duration(s.rng.ExpFloat64() * mean)
+	default:
+		return time.Duration(mean)
+	}
+}
+
+// validateThinkTimeDistribution checks the configured distribution name
+func validateThinkTimeDistribution(distribution string) error {
+	switch distribution {
+	case ThinkTimeFixed, ThinkTimeUniform, ThinkTimeExponential:
+		return nil
+	default:
+		return fmt.Errorf("unknown think time distribution: %s (expected %s, %s or %s)",
+			distribution, ThinkTimeFixed, ThinkTimeUniform, ThinkTimeExponential)
+	}
+}
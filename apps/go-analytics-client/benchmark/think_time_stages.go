@@ -0,0 +1,107 @@
+package benchmark
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ThinkTimeStage is a stretch of the measurement with its own think-time distribution
+type ThinkTimeStage struct {
+	Duration     time.Duration
+	Distribution string
+	MeanMs       int64
+}
+
+// ParseThinkTimeStages parses a comma-separated stage list such as
+// "60s:exponential:1000,60s:exponential:250". The last stage lasts until the run ends.
+func ParseThinkTimeStages(spec string) ([]ThinkTimeStage, error) {
+	if strings.TrimSpace(spec) == "" {
+		return nil, nil
+	}
+
+	var stages []ThinkTimeStage
+	for _, entry := range strings.Split(spec, ",") {
+		parts := strings.Split(strings.TrimSpace(entry), ":")
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("invalid think time stage %q (expected duration:distribution:meanMs)", entry)
+		}
+
+		duration, err := time.ParseDuration(parts[0])
+		if err != nil || duration <= 0 {
+			return nil, fmt.Errorf("invalid think time stage %q: duration must be positive", entry)
+		}
+		if err := validateThinkTimeDistribution(parts[1]); err != nil {
+			return nil, fmt.Errorf("invalid think time stage %q: %w", entry, err)
+		}
+		meanMs, err := strconv.ParseInt(parts[2], 10, 64)
+		if err != nil || meanMs < 0 {
+			return nil, fmt.Errorf("invalid think time stage %q: mean must be a non-negative number of milliseconds", entry)
+		}
+
+		stages = append(stages, ThinkTimeStage{Duration: duration, Distribution: parts[1], MeanMs: meanMs})
+	}
+	return stages, nil
+}
+
+// thinkTimeStageAt returns the index of the stage active at elapsed into the run
+func thinkTimeStageAt(stages []ThinkTimeStage, elapsed time.Duration) int {
+	for i, stage := range stages {
+		if elapsed < stage.Duration {
+			return i
+		}
+		elapsed -= stage.Duration
+	}
+	return len(stages) - 1
+}
+
+// newStageSamplers creates one think-time sampler per stage sharing a worker's rng
+func newStageSamplers(stages []ThinkTimeStage, rng *rand.Rand) []*ThinkTimeSampler {
+	samplers := make([]*ThinkTimeSampler, len(stages))
+	for i, stage := range stages {
+		samplers[i] = NewThinkTimeSampler(stage.Distribution, stage.MeanMs, rng)
+	}
+	return samplers
+}
+
+// StageStats collects successful-request latencies per think-time stage
+type StageStats struct {
+	mu        sync.Mutex
+	latencies []*LatencyRecorder
+	requests  []int64
+}
+
+// NewStageStats creates an aggregator for the given number of stages
+func NewStageStats(stages, significantDigits int) *StageStats {
+	s := &StageStats{
+		latencies: make([]*LatencyRecorder, stages),
+		requests:  make([]int64, stages),
+	}
+	for i := range s.latencies {
+		s.latencies[i] = NewLatencyRecorder(significantDigits)
+	}
+	return s
+}
+
+// Add records one request in the given stage (1-based, as in the Stage tag)
+func (s *StageStats) Add(result *QueryExecutionMetrics) {
+	index := result.Stage - 1
+	s.mu.Lock()
+	s.requests[index]++
+	s.mu.Unlock()
+
+	if result.Success {
+		s.latencies[index].Record(result.DurationMs)
+	}
+}
+
+// Snapshot returns the request count and successful latencies of a stage (0-based)
+func (s *StageStats) Snapshot(index int) (int64, *LatencyRecorder) {
+	s.mu.Lock()
+	requests := s.requests[index]
+	s.mu.Unlock()
+	return requests, s.latencies[index]
+}
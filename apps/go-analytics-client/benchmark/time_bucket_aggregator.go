@@ -0,0 +1,205 @@
+package benchmark
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"math"
+	"os"
+	"sort"
+	"sync/atomic"
+	"time"
+)
+
+// timeBucketReportPath returns where the bucketed aggregates for outputFile are written
+func timeBucketReportPath(outputFile string) string {
+	return artifactBase(outputFile) + ".buckets.jsonl"
+}
+
+// TimeBucket is the aggregate of the requests that started in one wall-clock
+// bucket. Latencies cover successful requests.
+type TimeBucket struct {
+	BucketStartMs int64   `json:"bucket_start_ms"`
+	BucketMs      int64   `json:"bucket_ms"`
+	Requests      int64   `json:"requests"`
+	Successes     int64   `json:"successes"`
+	Failures      int64   `json:"failures"`
+	MeanLatencyMs float64 `json:"mean_latency_ms"`
+	P99LatencyMs  float64 `json:"p99_latency_ms"`
+}
+
+// timeBucketState accumulates one bucket until it is written
+type timeBucketState struct {
+	requests  int64
+	successes int64
+	sumMs     float64
+	latencies *LatencyRecorder
+}
+
+// TimeBucketAggregator consumes the result stream next to the raw writer and
+// writes one aggregate line per bucket of bucketMs. Buckets are aligned to the
+// Unix epoch on each request's AbsoluteStartTimeMs, so runs line up bucket for
+// bucket. A bucket is written once lateness has passed since it ended, which
+// gives the requests that started in it time to complete; results arriving after
+// that are counted as late and left out.
+type TimeBucketAggregator struct {
+	outputFile string
+	bucketMs   int64
+	lateness   time.Duration
+	digits     int
+
+	resultChan   chan *QueryExecutionMetrics
+	writtenCount int64
+	droppedCount int64
+	lateCount    int64
+	done         chan struct{}
+	stopped      chan struct{}
+}
+
+// NewTimeBucketAggregator creates an aggregator writing to outputFile, with
+// bucket latencies recorded to the given number of significant digits
+func NewTimeBucketAggregator(outputFile string, bucketMs int64, lateness time.Duration, significantDigits int) *TimeBucketAggregator {
+	return &TimeBucketAggregator{
+		outputFile: outputFile,
+		bucketMs:   bucketMs,
+		lateness:   lateness,
+		digits:     significantDigits,
+		resultChan: make(chan *QueryExecutionMetrics, metricsQueueCapacity),
+		done:       make(chan struct{}),
+		stopped:    make(chan struct{}),
+	}
+}
+
+// WriteResult queues a result for aggregation
+func (a *TimeBucketAggregator) WriteResult(metrics *QueryExecutionMetrics) {
+	select {
+	case a.resultChan <- metrics:
+	case <-a.done:
+		atomic.AddInt64(&a.droppedCount, 1)
+		logWarnf("Attempted to write to closed time bucket aggregator")
+	default:
+		atomic.AddInt64(&a.droppedCount, 1)
+		logWarnf("Time bucket aggregator queue full, dropping result")
+	}
+}
+
+// Start aggregates queued results until ctx is cancelled, then drains the queue
+// and writes every remaining bucket
+func (a *TimeBucketAggregator) Start(ctx context.Context) {
+	defer close(a.stopped)
+
+	file, err := os.Create(a.outputFile)
+	if err != nil {
+		logErrorf("Failed to create time bucket output file: %v", err)
+		return
+	}
+	defer file.Close()
+	buffered := bufio.NewWriter(file)
+	defer buffered.Flush()
+	encoder := json.NewEncoder(buffered)
+
+	buckets := make(map[int64]*timeBucketState)
+	// Buckets starting before writtenThrough have already been written
+	writtenThrough := int64(0)
+
+	add := func(result *QueryExecutionMetrics) {
+		start := result.AbsoluteStartTimeMs - result.AbsoluteStartTimeMs%a.bucketMs
+		if start < writtenThrough {
+			atomic.AddInt64(&a.lateCount, 1)
+			return
+		}
+		state, ok := buckets[start]
+		if !ok {
+			state = &timeBucketState{latencies: NewLatencyRecorder(a.digits)}
+			buckets[start] = state
+		}
+		state.requests++
+		if result.Success {
+			state.successes++
+			state.sumMs += result.DurationMs
+			state.latencies.Record(result.DurationMs)
+		}
+		atomic.AddInt64(&a.writtenCount, 1)
+	}
+
+	// writeBefore writes, in order, every bucket ending at or before cutoffMs
+	writeBefore := func(cutoffMs int64) {
+		var starts []int64
+		for start := range buckets {
+			if start+a.bucketMs <= cutoffMs {
+				starts = append(starts, start)
+			}
+		}
+		sort.Slice(starts, func(i, j int) bool { return starts[i] < starts[j] })
+
+		for _, start := range starts {
+			state := buckets[start]
+			bucket := TimeBucket{
+				BucketStartMs: start,
+				BucketMs:      a.bucketMs,
+				Requests:      state.requests,
+				Successes:     state.successes,
+				Failures:      state.requests - state.successes,
+				P99LatencyMs:  state.latencies.Percentiles(99)[99],
+			}
+			if state.successes > 0 {
+				bucket.MeanLatencyMs = state.sumMs / float64(state.successes)
+			}
+			if err := encoder.Encode(bucket); err != nil {
+				logErrorf("Failed to write time bucket: %v", err)
+			}
+			delete(buckets, start)
+			writtenThrough = start + a.bucketMs
+		}
+		if len(starts) > 0 {
+			if err := buffered.Flush(); err != nil {
+				logErrorf("Failed to flush time bucket output file: %v", err)
+			}
+		}
+	}
+
+	ticker := time.NewTicker(time.Duration(a.bucketMs) * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			close(a.done)
+			for {
+				select {
+				case result := <-a.resultChan:
+					add(result)
+				default:
+					writeBefore(math.MaxInt64)
+					if late := atomic.LoadInt64(&a.lateCount); late > 0 {
+						logWarnf("%d results arrived after their time bucket was written and are not in %s",
+							late, a.outputFile)
+					}
+					return
+				}
+			}
+		case result := <-a.resultChan:
+			add(result)
+		case <-ticker.C:
+			writeBefore(time.Now().Add(-a.lateness).UnixMilli())
+		}
+	}
+}
+
+func (a *TimeBucketAggregator) Wait() {
+	<-a.stopped
+}
+
+// GetWrittenCount returns the number of results aggregated into buckets
+func (a *TimeBucketAggregator) GetWrittenCount() int64 {
+	return atomic.LoadInt64(&a.writtenCount)
+}
+
+// GetDroppedCount returns the number of results lost to a full queue or a closed writer
+func (a *TimeBucketAggregator) GetDroppedCount() int64 {
+	return atomic.LoadInt64(&a.droppedCount)
+}
+
+func (a *TimeBucketAggregator) GetQueueSize() int {
+	return len(a.resultChan)
+}
@@ -0,0 +1,197 @@
+package benchmark
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/couchbase/gocb/v2"
+	cbanalytics "github.com/couchbase/gocbanalytics"
+)
+
+// Connection string schemes for plain and TLS-secured clusters
+const (
+	schemeCouchbase  = "couchbase://"
+	schemeCouchbases = "couchbases://"
+)
+
+// Analytics service ports, plain and TLS
+const (
+	analyticsPortPlain = 8095
+	analyticsPortTLS   = 18095
+)
+
+// isTLSConnectionString reports whether the connection string selects TLS
+func isTLSConnectionString(connectionString string) bool {
+	return strings.HasPrefix(connectionString, schemeCouchbases) || strings.HasPrefix(connectionString, "https://")
+}
+
+// validateTLSOptions checks the TLS settings only accompany a TLS connection string
+func validateTLSOptions(config Configuration) error {
+	if (config.TLSCACertPath != "" || config.TLSInsecureSkipVerify) && !isTLSConnectionString(config.ConnectionString) {
+		return fmt.Errorf("BENCHMARK_TLS_CA_CERT_PATH and BENCHMARK_TLS_INSECURE_SKIP_VERIFY require a %s or https:// connection string",
+			schemeCouchbases)
+	}
+	if usesCertificateAuth(config) && !isTLSConnectionString(config.ConnectionString) {
+		return fmt.Errorf("client certificate authentication requires a %s connection string", schemeCouchbases)
+	}
+	return nil
+}
+
+// usesCertificateAuth reports whether a client certificate is configured
+func usesCertificateAuth(config Configuration) bool {
+	return config.TLSClientCertPath != "" || config.TLSClientKeyPath != ""
+}
+
+// validateAuth checks that exactly one way of authenticating is fully specified:
+// a username and password, or a client certificate and key
+func validateAuth(config Configuration) error {
+	password := config.Username != "" || config.Password != ""
+	if usesCertificateAuth(config) {
+		if password {
+			return fmt.Errorf("set either CLUSTER_USERNAME and CLUSTER_PASSWORD or BENCHMARK_TLS_CLIENT_CERT_PATH and " +
+				"BENCHMARK_TLS_CLIENT_KEY_PATH, not both")
+		}
+		if config.TLSClientCertPath == "" || config.TLSClientKeyPath == "" {
+			return fmt.Errorf("client certificate authentication needs both BENCHMARK_TLS_CLIENT_CERT_PATH and BENCHMARK_TLS_CLIENT_KEY_PATH")
+		}
+		// gocbanalytics has no client certificate credential and its SecurityOptions
+		// cannot present one, so certificate authentication is operational only
+		if config.SDKType != SDKTypeOperational {
+			return fmt.Errorf("client certificate authentication is operational only: gocbanalytics only offers a basic "+
+				"auth credential and cannot present a client certificate, so use CLUSTER_USERNAME and CLUSTER_PASSWORD "+
+				"with the %s SDK type", config.SDKType)
+		}
+		return nil
+	}
+	if config.Username == "" || config.Password == "" {
+		return fmt.Errorf("missing required settings: CLUSTER_USERNAME (username) and CLUSTER_PASSWORD (password), " +
+			"or BENCHMARK_TLS_CLIENT_CERT_PATH and BENCHMARK_TLS_CLIENT_KEY_PATH")
+	}
+	return nil
+}
+
+// operationalAuthenticator builds the gocb authenticator: the client certificate
+// when one is configured, otherwise the username and password
+func operationalAuthenticator(config Configuration) (gocb.Authenticator, error) {
+	if !usesCertificateAuth(config) {
+		return gocb.PasswordAuthenticator{Username: config.Username, Password: config.Password}, nil
+	}
+	cert, err := tls.LoadX509KeyPair(config.TLSClientCertPath, config.TLSClientKeyPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load client certificate: %w", err)
+	}
+	return gocb.CertificateAuthenticator{ClientCertificate: &cert}, nil
+}
+
+// validateAnalyticsEndpoint checks the settings that only the enterprise SDK's
+// direct analytics connection understands
+func validateAnalyticsEndpoint(config Configuration) error {
+	if config.AnalyticsPort < 0 || config.AnalyticsPort > 65535 {
+		return fmt.Errorf("BENCHMARK_ANALYTICS_PORT must be a valid port, got %d", config.AnalyticsPort)
+	}
+	if config.SDKType == SDKTypeEnterprise {
+		if config.AnalyticsPort > 0 && isAnalyticsURL(config.ConnectionString) {
+			return fmt.Errorf("BENCHMARK_ANALYTICS_PORT cannot be combined with a connection string URL; put the port in the URL")
+		}
+		return nil
+	}
+	// A comparison run's enterprise half uses the port, but its operational half
+	// needs a cluster connection string
+	if config.AnalyticsPort > 0 && config.SDKType != SDKTypeBoth {
+		return fmt.Errorf("BENCHMARK_ANALYTICS_PORT only applies to the %s SDK", SDKTypeEnterprise)
+	}
+	if isAnalyticsURL(config.ConnectionString) {
+		return fmt.Errorf("an http(s):// connection string only applies to the %s SDK", SDKTypeEnterprise)
+	}
+	return nil
+}
+
+// enterpriseAnalyticsURL turns the cluster connection string into the analytics
+// endpoint: https on the TLS port for couchbases://, http on the plain port
+// otherwise, with port overriding the default when set. Only the first host is
+// used, without any port it carries; IPv6 hosts may be bracketed. A connection
+// string that is already an http(s) URL is used as-is.
+func enterpriseAnalyticsURL(connectionString string, port int) (string, error) {
+	if isAnalyticsURL(connectionString) {
+		return strings.TrimSuffix(connectionString, "/"), nil
+	}
+
+	scheme, defaultPort := "http", analyticsPortPlain
+	host := strings.TrimPrefix(connectionString, schemeCouchbase)
+	if isTLSConnectionString(connectionString) {
+		scheme, defaultPort = "https", analyticsPortTLS
+		host = strings.TrimPrefix(connectionString, schemeCouchbases)
+	}
+	if port <= 0 {
+		port = defaultPort
+	}
+	hosts := strings.FieldsFunc(host, func(r rune) bool { return r == ',' || r == '/' || r == '?' })
+	if len(hosts) == 0 {
+		return "", fmt.Errorf("connection string %q has no host", connectionString)
+	}
+	host = hosts[0]
+	if withoutPort, _, err := net.SplitHostPort(host); err == nil {
+		host = withoutPort
+	} else {
+		// No port: a bracketed IPv6 literal keeps its brackets until JoinHostPort
+		host = strings.TrimSuffix(strings.TrimPrefix(host, "["), "]")
+	}
+	if host == "" {
+		return "", fmt.Errorf("connection string %q has no host", connectionString)
+	}
+	return fmt.Sprintf("%s://%s", scheme, net.JoinHostPort(host, strconv.Itoa(port))), nil
+}
+
+// isAnalyticsURL reports whether the connection string is a direct analytics
+// endpoint URL rather than a cluster connection string
+func isAnalyticsURL(connectionString string) bool {
+	return strings.HasPrefix(connectionString, "http://") || strings.HasPrefix(connectionString, "https://")
+}
+
+// loadCACertPool reads a PEM file of trusted CA certificates
+func loadCACertPool(path string) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA certificate: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no PEM certificates found in %s", path)
+	}
+	return pool, nil
+}
+
+// operationalSecurityConfig builds the gocb TLS settings. gocb enables TLS itself
+// for couchbases:// and trusts the system roots unless a CA is configured.
+func operationalSecurityConfig(config Configuration) (gocb.SecurityConfig, error) {
+	security := gocb.SecurityConfig{TLSSkipVerify: config.TLSInsecureSkipVerify}
+	if config.TLSCACertPath != "" {
+		pool, err := loadCACertPool(config.TLSCACertPath)
+		if err != nil {
+			return security, err
+		}
+		security.TLSRootCAs = pool
+	}
+	return security, nil
+}
+
+// enterpriseSecurityOptions builds the gocbanalytics TLS settings, or nil for a
+// plain connection
+func enterpriseSecurityOptions(config Configuration) *cbanalytics.SecurityOptions {
+	if !isTLSConnectionString(config.ConnectionString) {
+		return nil
+	}
+	security := cbanalytics.NewSecurityOptions()
+	if config.TLSCACertPath != "" {
+		security = security.SetTrustOnlyPemFile(config.TLSCACertPath)
+	}
+	if config.TLSInsecureSkipVerify {
+		security = security.SetDisableServerCertificateVerification(true)
+	}
+	return security
+}
@@ -0,0 +1,137 @@
+package benchmark
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+)
+
+// VerificationResult is one run of the periodic correctness-check query
+type VerificationResult struct {
+	TimestampMs     int64             `json:"timestamp_ms"`
+	Query           string            `json:"query"`
+	Success         bool              `json:"success"`
+	ErrorMessage    string            `json:"error_message,omitempty"`
+	DurationMs      float64           `json:"duration_ms"`
+	Rows            []json.RawMessage `json:"rows,omitempty"`
+	MatchesBaseline bool              `json:"matches_baseline"`
+	Run             int64             `json:"run"`
+}
+
+// Verifier periodically runs a verification query outside the worker pool and
+// records its results to a separate file, so they never reach the latency metrics
+type Verifier struct {
+	handler    AnalyticsSDKHandler
+	query      string
+	interval   time.Duration
+	outputFile string
+
+	runs       int64
+	failures   int64
+	mismatches int64
+	done       chan struct{}
+}
+
+// NewVerifier creates a verifier for the given query and interval
+func NewVerifier(handler AnalyticsSDKHandler, query string, interval time.Duration, outputFile string) *Verifier {
+	return &Verifier{
+		handler:    handler,
+		query:      query,
+		interval:   interval,
+		outputFile: outputFile,
+		done:       make(chan struct{}),
+	}
+}
+
+// Start runs the verification query on every interval until ctx is cancelled
+func (v *Verifier) Start(ctx context.Context) {
+	defer close(v.done)
+
+	if err := os.MkdirAll(filepath.Dir(v.outputFile), 0755); err != nil {
+		logErrorf("Failed to create verification output directory: %v", err)
+		return
+	}
+	file, err := os.Create(v.outputFile)
+	if err != nil {
+		logErrorf("Failed to create verification output file: %v", err)
+		return
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	ticker := time.NewTicker(v.interval)
+	defer ticker.Stop()
+
+	// The first successful result is the baseline later results must match
+	var baseline []json.RawMessage
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			result := v.runOnce()
+			if result.Success {
+				if baseline == nil {
+					baseline = result.Rows
+				}
+				result.MatchesBaseline = rowsEqual(baseline, result.Rows)
+				if !result.MatchesBaseline {
+					atomic.AddInt64(&v.mismatches, 1)
+					logWarnf("Verification query result differs from baseline (run #%d)", result.Run)
+				}
+			} else {
+				atomic.AddInt64(&v.failures, 1)
+				logWarnf("Verification query failed: %s", result.ErrorMessage)
+			}
+
+			if err := encoder.Encode(result); err != nil {
+				logErrorf("Failed to encode verification result: %v", err)
+			}
+		}
+	}
+}
+
+// Wait blocks until the verifier has stopped
+func (v *Verifier) Wait() {
+	<-v.done
+}
+
+// Snapshot returns the number of runs, failures and baseline mismatches
+func (v *Verifier) Snapshot() (int64, int64, int64) {
+	return atomic.LoadInt64(&v.runs), atomic.LoadInt64(&v.failures), atomic.LoadInt64(&v.mismatches)
+}
+
+func (v *Verifier) runOnce() *VerificationResult {
+	run := atomic.AddInt64(&v.runs, 1)
+	start := time.Now()
+	rows, err := v.handler.FetchRows(v.query)
+
+	result := &VerificationResult{
+		TimestampMs: start.UnixMilli(),
+		Query:       v.query,
+		Success:     err == nil,
+		DurationMs:  float64(time.Since(start).Nanoseconds()) / 1_000_000.0,
+		Rows:        rows,
+		Run:         run,
+	}
+	if err != nil {
+		result.ErrorMessage = err.Error()
+	}
+	return result
+}
+
+func rowsEqual(a, b []json.RawMessage) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !bytes.Equal(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
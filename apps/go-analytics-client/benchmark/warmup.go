@@ -0,0 +1,100 @@
+package benchmark
+
+import (
+	"fmt"
+	"math"
+	"sync"
+)
+
+// Supported warmup modes
+const (
+	WarmupModeFixed    = "fixed"
+	WarmupModeAdaptive = "adaptive"
+)
+
+// validateWarmupMode checks the configured warmup mode name
+func validateWarmupMode(mode string) error {
+	switch mode {
+	case WarmupModeFixed, WarmupModeAdaptive:
+		return nil
+	default:
+		return fmt.Errorf("unknown warmup mode: %s (expected %s or %s)", mode, WarmupModeFixed, WarmupModeAdaptive)
+	}
+}
+
+// WarmupStabilizer decides when warmup latencies have settled. Successful
+// latencies are grouped into consecutive windows of a fixed size, and warmup is
+// stable once enough windows in a row have a coefficient of variation
+// (stddev / mean) at or below the threshold.
+type WarmupStabilizer struct {
+	mu           sync.Mutex
+	windowSize   int
+	thresholdPct float64
+	required     int
+
+	window      []float64
+	consecutive int
+	windows     int
+	lastCVPct   float64
+}
+
+// NewWarmupStabilizer creates a stabilizer over windows of windowSize requests
+func NewWarmupStabilizer(windowSize int, thresholdPct float64, required int) *WarmupStabilizer {
+	return &WarmupStabilizer{
+		windowSize:   windowSize,
+		thresholdPct: thresholdPct,
+		required:     required,
+		window:       make([]float64, 0, windowSize),
+	}
+}
+
+// Add records one successful latency and reports whether warmup is now stable
+func (s *WarmupStabilizer) Add(durationMs float64) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.consecutive >= s.required {
+		return true
+	}
+
+	s.window = append(s.window, durationMs)
+	if len(s.window) < s.windowSize {
+		return false
+	}
+
+	s.windows++
+	s.lastCVPct = coefficientOfVariation(s.window) * 100
+	if s.lastCVPct <= s.thresholdPct {
+		s.consecutive++
+	} else {
+		s.consecutive = 0
+	}
+	s.window = s.window[:0]
+	return s.consecutive >= s.required
+}
+
+// Snapshot returns the number of completed windows and the last window's CV in percent
+func (s *WarmupStabilizer) Snapshot() (int, float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.windows, s.lastCVPct
+}
+
+// coefficientOfVariation returns the population stddev divided by the mean
+func coefficientOfVariation(values []float64) float64 {
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	mean := sum / float64(len(values))
+	if mean == 0 {
+		return 0
+	}
+
+	var squares float64
+	for _, v := range values {
+		squares += (v - mean) * (v - mean)
+	}
+	return math.Sqrt(squares/float64(len(values))) / mean
+}
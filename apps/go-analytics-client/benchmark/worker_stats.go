@@ -0,0 +1,65 @@
+package benchmark
+
+import "sync"
+
+// WorkerSummary is the request count and latency of one worker goroutine
+type WorkerSummary struct {
+	WorkerID      int                `json:"worker_id"`
+	TotalRequests int64              `json:"total_requests"`
+	Successes     int64              `json:"successes"`
+	Latency       LatencyPercentiles `json:"latency"`
+}
+
+// WorkerStats aggregates requests and successful latencies per worker, to expose
+// workers that the scheduler starves or favours
+type WorkerStats struct {
+	mu        sync.Mutex
+	latencies []*LatencyRecorder
+	requests  []int64
+	successes []int64
+}
+
+// NewWorkerStats creates an aggregator for the given number of workers
+func NewWorkerStats(workers, significantDigits int) *WorkerStats {
+	s := &WorkerStats{
+		latencies: make([]*LatencyRecorder, workers),
+		requests:  make([]int64, workers),
+		successes: make([]int64, workers),
+	}
+	for i := range s.latencies {
+		s.latencies[i] = NewLatencyRecorder(significantDigits)
+	}
+	return s
+}
+
+// Add records one request of the worker in its WorkerID tag
+func (s *WorkerStats) Add(result *QueryExecutionMetrics) {
+	index := *result.WorkerID
+	s.mu.Lock()
+	s.requests[index]++
+	if result.Success {
+		s.successes[index]++
+	}
+	s.mu.Unlock()
+
+	if result.Success {
+		s.latencies[index].Record(result.DurationMs)
+	}
+}
+
+// Summaries returns every worker's outcome in worker order
+func (s *WorkerStats) Summaries() []WorkerSummary {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	summaries := make([]WorkerSummary, len(s.requests))
+	for i := range s.requests {
+		summaries[i] = WorkerSummary{
+			WorkerID:      i,
+			TotalRequests: s.requests[i],
+			Successes:     s.successes[i],
+			Latency:       NewLatencyPercentiles(s.latencies[i].Percentiles(summaryPercentiles...)),
+		}
+	}
+	return summaries
+}